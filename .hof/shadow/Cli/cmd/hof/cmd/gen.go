@@ -6,17 +6,30 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/hofstadter-io/hof/cmd/hof/cmd/gen"
+
 	"github.com/hofstadter-io/hof/cmd/hof/flags"
 
 	"github.com/hofstadter-io/hof/cmd/hof/ga"
 )
 
-var genLong = `  generate all the things, from code to data to config...`
+var genLong = `  generate all the things, from code to data to config...
+
+  Generators can read In data from JSON, YAML, CSV, TOML, XML, and SQLite files with InData.
+  SQLite needs the sqlite3 command-line program, version 3.33 or later, on the PATH.`
 
 func init() {
 
-	GenCmd.Flags().BoolVarP(&(flags.GenFlags.Stats), "stats", "s", false, "Print generator statistics")
-	GenCmd.Flags().StringSliceVarP(&(flags.GenFlags.Generator), "generator", "g", nil, "Generators to run, default is all discovered")
+	GenCmd.PersistentFlags().BoolVarP(&(flags.GenPflags.Stats), "stats", "s", false, "Print generator statistics")
+	GenCmd.PersistentFlags().StringSliceVarP(&(flags.GenPflags.Generator), "generator", "g", nil, "Generators to run, default is all discovered")
+	GenCmd.PersistentFlags().StringSliceVarP(&(flags.GenPflags.Tags), "tags", "t", nil, "Cue tags to inject into @tag() fields, as key=value or short value")
+	GenCmd.PersistentFlags().StringSliceVarP(&(flags.GenPflags.Set), "set", "", nil, "Values to fill into open fields at load time, as path[:type]=value, where values are strings unless quoted or typed as int, number, bool, or json")
+	GenCmd.PersistentFlags().StringSliceVarP(&(flags.GenPflags.Only), "only", "", nil, "Only generate output files matching these globs")
+	GenCmd.PersistentFlags().StringSliceVarP(&(flags.GenPflags.Exclude), "exclude", "", nil, "Do not generate output files matching these globs")
+	GenCmd.PersistentFlags().StringVarP(&(flags.GenPflags.Report), "report", "", "", "Write a machine readable report [json, junit]")
+	GenCmd.PersistentFlags().StringVarP(&(flags.GenPflags.ReportFile), "report-file", "", "", "File to write the --report to, it is required with --report")
+	GenCmd.PersistentFlags().StringSliceVarP(&(flags.GenPflags.FailOn), "fail-on", "", nil, "Exit non-zero when output has [conflict, change]")
+	GenCmd.PersistentFlags().IntVarP(&(flags.GenPflags.BatchSize), "batch-size", "", 0, "Decode, render, and write files this many at a time, bounding memory for large generators")
 }
 
 func GenRun(args []string) (err error) {
@@ -90,4 +103,8 @@ func init() {
 	GenCmd.SetHelpFunc(thelp)
 	GenCmd.SetUsageFunc(tusage)
 
+	GenCmd.AddCommand(cmdgen.AdoptCmd)
+	GenCmd.AddCommand(cmdgen.EjectCmd)
+	GenCmd.AddCommand(cmdgen.ServeCmd)
+
 }
@@ -0,0 +1,102 @@
+package cmdgen
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hofstadter-io/hof/lib"
+
+	"github.com/hofstadter-io/hof/cmd/hof/ga"
+)
+
+var ejectLong = `eject files or generators from hof ownership
+
+Targets are generator names or output paths, directories eject all files under them.
+The shadow is removed and the targets are recorded in .hof/ejected, so the files
+become plain user files which gen no longer renders, merges, or deletes.
+A "Code generated ... DO NOT EDIT." first line is removed from the files.
+Targets without generated files, like a generator which never ran, are a no-op.`
+
+func EjectRun(targets []string) (err error) {
+
+	err = lib.GenEject(targets)
+
+	return err
+}
+
+var EjectCmd = &cobra.Command{
+
+	Use: "eject <generator|path...>",
+
+	Short: "eject files or generators from hof ownership",
+
+	Long: ejectLong,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+
+		ga.SendCommandPath(cmd.CommandPath())
+
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		// Argument Parsing
+
+		if 0 >= len(args) {
+			fmt.Println("missing required argument: 'targets'")
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		var targets []string
+
+		if 0 < len(args) {
+
+			targets = args[0:]
+
+		}
+
+		err = EjectRun(targets)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	extra := func(cmd *cobra.Command) bool {
+
+		return false
+	}
+
+	ohelp := EjectCmd.HelpFunc()
+	ousage := EjectCmd.UsageFunc()
+	help := func(cmd *cobra.Command, args []string) {
+		if extra(cmd) {
+			return
+		}
+		ohelp(cmd, args)
+	}
+	usage := func(cmd *cobra.Command) error {
+		if extra(cmd) {
+			return nil
+		}
+		return ousage(cmd)
+	}
+
+	thelp := func(cmd *cobra.Command, args []string) {
+		ga.SendCommandPath(cmd.CommandPath() + " help")
+		help(cmd, args)
+	}
+	tusage := func(cmd *cobra.Command) error {
+		ga.SendCommandPath(cmd.CommandPath() + " usage")
+		return usage(cmd)
+	}
+	EjectCmd.SetHelpFunc(thelp)
+	EjectCmd.SetUsageFunc(tusage)
+
+}
@@ -0,0 +1,100 @@
+package cmdgen
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+
+	"github.com/hofstadter-io/hof/lib"
+
+	"github.com/hofstadter-io/hof/cmd/hof/ga"
+)
+
+var serveLong = `serve generators over a local HTTP/JSON API
+
+Keeps the Cue, templates, and shadow loaded between requests,
+reloading when the Cue, imports, templates, or data change, so editors and hooks can
+render without the startup cost.
+
+  GET  /generators                           list generators and their output files
+  GET  /render?generator=<name>&filepath=<p> render a single file, nothing is written
+  POST /gen                                  run the generators and write the output
+  POST /reload                               reload the Cue files
+
+Only local hosts and pages can reach it, and POSTs must send Content-Type: application/json.`
+
+func init() {
+
+	ServeCmd.Flags().StringVarP(&(flags.GenServeFlags.Addr), "addr", "", "localhost:2323", "Address to listen on, keep it local")
+}
+
+func ServeRun(args []string) (err error) {
+
+	err = lib.GenServe(args, flags.GenPflags, flags.GenServeFlags.Addr)
+
+	return err
+}
+
+var ServeCmd = &cobra.Command{
+
+	Use: "serve [files...]",
+
+	Short: "serve generators over a local HTTP/JSON API",
+
+	Long: serveLong,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+
+		ga.SendCommandPath(cmd.CommandPath())
+
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		// Argument Parsing
+
+		err = ServeRun(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	extra := func(cmd *cobra.Command) bool {
+
+		return false
+	}
+
+	ohelp := ServeCmd.HelpFunc()
+	ousage := ServeCmd.UsageFunc()
+	help := func(cmd *cobra.Command, args []string) {
+		if extra(cmd) {
+			return
+		}
+		ohelp(cmd, args)
+	}
+	usage := func(cmd *cobra.Command) error {
+		if extra(cmd) {
+			return nil
+		}
+		return ousage(cmd)
+	}
+
+	thelp := func(cmd *cobra.Command, args []string) {
+		ga.SendCommandPath(cmd.CommandPath() + " help")
+		help(cmd, args)
+	}
+	tusage := func(cmd *cobra.Command) error {
+		ga.SendCommandPath(cmd.CommandPath() + " usage")
+		return usage(cmd)
+	}
+	ServeCmd.SetHelpFunc(thelp)
+	ServeCmd.SetUsageFunc(tusage)
+
+}
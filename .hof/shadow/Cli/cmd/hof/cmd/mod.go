@@ -30,9 +30,15 @@ mod file format:
   replace <module path> => <local path>
   ...`
 
+func init() {
+
+	ModCmd.PersistentFlags().BoolVarP(&(flags.ModPflags.Offline), "offline", "", false, "only use the module cache, fail instead of fetching")
+}
+
 func ModPersistentPreRun(args []string) (err error) {
 
 	mod.InitLangs()
+	mod.SetOffline(flags.ModPflags.Offline)
 
 	return err
 }
@@ -111,8 +117,12 @@ func init() {
 	ModCmd.AddCommand(cmdmod.InfoCmd)
 	ModCmd.AddCommand(cmdmod.ConvertCmd)
 	ModCmd.AddCommand(cmdmod.GraphCmd)
+	ModCmd.AddCommand(cmdmod.WhyCmd)
 	ModCmd.AddCommand(cmdmod.StatusCmd)
 	ModCmd.AddCommand(cmdmod.InitCmd)
+	ModCmd.AddCommand(cmdmod.GetCmd)
+	ModCmd.AddCommand(cmdmod.ListCmd)
+	ModCmd.AddCommand(cmdmod.CacheCmd)
 	ModCmd.AddCommand(cmdmod.TidyCmd)
 	ModCmd.AddCommand(cmdmod.VendorCmd)
 	ModCmd.AddCommand(cmdmod.VerifyCmd)
@@ -228,8 +238,10 @@ module <module-path>
 # Required dependencies section
 require (
 	# <module-path> <module-semver>
-	github.com/hof-lang/cuemod--cli-golang v0.0.0      # This is latest on HEAD
+	github.com/hof-lang/cuemod--cli-golang v0.0.0      # This is HEAD on the default branch, recorded as a pseudo-version
 	github.com/hof-lang/cuemod--cli-golang v0.1.5      # This is a tag v0.1.5 (can omit 'v' in tag, but not here)
+	github.com/hof-lang/cuemod--cli-golang main        # A branch or commit, also recorded as a pseudo-version
+	github.com/hof-lang/cuemod--cli-golang v0.1.6-0.20200601000000-abcdef123456  # A pseudo-version, an untagged commit
 )
 
 # replace <module-path> => <module-path|local-path> [version]
@@ -368,6 +380,9 @@ cue: {
 	NoLoad: false
 	CommandInit: [[string]]
 	CommandGraph: [[string]]
+	CommandGet: [[string]]
+	CommandList: [[string]]
+	CommandWhy: [[string]]
 	CommandTidy: [[string]]
 	CommandVendor: [[string]]
 	CommandVerify: [[string]]
@@ -0,0 +1,94 @@
+package cmdcache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+
+	"github.com/hofstadter-io/hof/lib/mod"
+
+	"github.com/hofstadter-io/hof/cmd/hof/ga"
+)
+
+var cleanLong = `remove cached modules, matching the patterns or --all of them`
+
+func init() {
+
+	CleanCmd.Flags().BoolVarP(&(flags.ModCacheCleanFlags.All), "all", "", false, "remove every cached module")
+	CleanCmd.Flags().BoolVarP(&(flags.ModCacheCleanFlags.DryRun), "dry-run", "", false, "print what would be removed")
+}
+
+func CleanRun(args []string) (err error) {
+
+	err = mod.CacheClean(args, flags.ModCacheCleanFlags.All, flags.ModCacheCleanFlags.DryRun)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return err
+}
+
+var CleanCmd = &cobra.Command{
+
+	Use: "clean [patterns...]",
+
+	Short: "remove cached modules, matching the patterns or --all of them",
+
+	Long: cleanLong,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+
+		ga.SendCommandPath(cmd.CommandPath())
+
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		// Argument Parsing
+
+		err = CleanRun(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	extra := func(cmd *cobra.Command) bool {
+
+		return false
+	}
+
+	ohelp := CleanCmd.HelpFunc()
+	ousage := CleanCmd.UsageFunc()
+	help := func(cmd *cobra.Command, args []string) {
+		if extra(cmd) {
+			return
+		}
+		ohelp(cmd, args)
+	}
+	usage := func(cmd *cobra.Command) error {
+		if extra(cmd) {
+			return nil
+		}
+		return ousage(cmd)
+	}
+
+	thelp := func(cmd *cobra.Command, args []string) {
+		ga.SendCommandPath(cmd.CommandPath() + " help")
+		help(cmd, args)
+	}
+	tusage := func(cmd *cobra.Command) error {
+		ga.SendCommandPath(cmd.CommandPath() + " usage")
+		return usage(cmd)
+	}
+	CleanCmd.SetHelpFunc(thelp)
+	CleanCmd.SetUsageFunc(tusage)
+
+}
@@ -0,0 +1,86 @@
+package cmdcache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hofstadter-io/hof/lib/mod"
+
+	"github.com/hofstadter-io/hof/cmd/hof/ga"
+)
+
+var listLong = `list cached modules and their sizes`
+
+func ListRun(args []string) (err error) {
+
+	err = mod.CacheList(args)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return err
+}
+
+var ListCmd = &cobra.Command{
+
+	Use: "list [patterns...]",
+
+	Short: "list cached modules and their sizes",
+
+	Long: listLong,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+
+		ga.SendCommandPath(cmd.CommandPath())
+
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		// Argument Parsing
+
+		err = ListRun(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	extra := func(cmd *cobra.Command) bool {
+
+		return false
+	}
+
+	ohelp := ListCmd.HelpFunc()
+	ousage := ListCmd.UsageFunc()
+	help := func(cmd *cobra.Command, args []string) {
+		if extra(cmd) {
+			return
+		}
+		ohelp(cmd, args)
+	}
+	usage := func(cmd *cobra.Command) error {
+		if extra(cmd) {
+			return nil
+		}
+		return ousage(cmd)
+	}
+
+	thelp := func(cmd *cobra.Command, args []string) {
+		ga.SendCommandPath(cmd.CommandPath() + " help")
+		help(cmd, args)
+	}
+	tusage := func(cmd *cobra.Command) error {
+		ga.SendCommandPath(cmd.CommandPath() + " usage")
+		return usage(cmd)
+	}
+	ListCmd.SetHelpFunc(thelp)
+	ListCmd.SetUsageFunc(tusage)
+
+}
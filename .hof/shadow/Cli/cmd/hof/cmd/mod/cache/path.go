@@ -0,0 +1,82 @@
+package cmdcache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hofstadter-io/hof/lib/mod"
+
+	"github.com/hofstadter-io/hof/cmd/hof/ga"
+)
+
+var pathLong = `print the module cache directory`
+
+func PathRun(args []string) (err error) {
+
+	err = mod.CachePath()
+
+	return err
+}
+
+var PathCmd = &cobra.Command{
+
+	Use: "path",
+
+	Short: "print the module cache directory",
+
+	Long: pathLong,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+
+		ga.SendCommandPath(cmd.CommandPath())
+
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		// Argument Parsing
+
+		err = PathRun(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	extra := func(cmd *cobra.Command) bool {
+
+		return false
+	}
+
+	ohelp := PathCmd.HelpFunc()
+	ousage := PathCmd.UsageFunc()
+	help := func(cmd *cobra.Command, args []string) {
+		if extra(cmd) {
+			return
+		}
+		ohelp(cmd, args)
+	}
+	usage := func(cmd *cobra.Command) error {
+		if extra(cmd) {
+			return nil
+		}
+		return ousage(cmd)
+	}
+
+	thelp := func(cmd *cobra.Command, args []string) {
+		ga.SendCommandPath(cmd.CommandPath() + " help")
+		help(cmd, args)
+	}
+	tusage := func(cmd *cobra.Command) error {
+		ga.SendCommandPath(cmd.CommandPath() + " usage")
+		return usage(cmd)
+	}
+	PathCmd.SetHelpFunc(thelp)
+	PathCmd.SetUsageFunc(tusage)
+
+}
@@ -0,0 +1,97 @@
+package cmdmod
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+
+	"github.com/hofstadter-io/hof/lib/mod"
+
+	"github.com/hofstadter-io/hof/cmd/hof/ga"
+)
+
+var listLong = `list the selected module versions
+
+Prints the main module and then each dependency as
+'module version [=> replacement version]', like 'go list -m all'.
+With -u, the latest version is shown in brackets when it is greater.`
+
+func init() {
+
+	ListCmd.Flags().BoolVarP(&(flags.ModListFlags.Updates), "updates", "u", false, "show available upgrades")
+}
+
+func ListRun(args []string) (err error) {
+
+	err = mod.ListLangs(args, flags.ModListFlags.Updates)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return err
+}
+
+var ListCmd = &cobra.Command{
+
+	Use: "list [langs...]",
+
+	Short: "list the selected module versions",
+
+	Long: listLong,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+
+		ga.SendCommandPath(cmd.CommandPath())
+
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		// Argument Parsing
+
+		err = ListRun(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	extra := func(cmd *cobra.Command) bool {
+
+		return false
+	}
+
+	ohelp := ListCmd.HelpFunc()
+	ousage := ListCmd.UsageFunc()
+	help := func(cmd *cobra.Command, args []string) {
+		if extra(cmd) {
+			return
+		}
+		ohelp(cmd, args)
+	}
+	usage := func(cmd *cobra.Command) error {
+		if extra(cmd) {
+			return nil
+		}
+		return ousage(cmd)
+	}
+
+	thelp := func(cmd *cobra.Command, args []string) {
+		ga.SendCommandPath(cmd.CommandPath() + " help")
+		help(cmd, args)
+	}
+	tusage := func(cmd *cobra.Command) error {
+		ga.SendCommandPath(cmd.CommandPath() + " usage")
+		return usage(cmd)
+	}
+	ListCmd.SetHelpFunc(thelp)
+	ListCmd.SetUsageFunc(tusage)
+
+}
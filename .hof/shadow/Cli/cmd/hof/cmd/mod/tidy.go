@@ -11,7 +11,13 @@ import (
 	"github.com/hofstadter-io/hof/cmd/hof/ga"
 )
 
-var tidyLong = `add missinad and remove unused modules`
+var tidyLong = `add missing and remove unused modules
+
+Imports are discovered by scanning the module's files with the
+Introspect globs and regexes from the language's modder config.
+Missing modules are required at their latest version, requires
+which are no longer imported are dropped, and the sum file is
+rewritten for the resolved dependencies.`
 
 func TidyRun(args []string) (err error) {
 
@@ -28,7 +34,7 @@ var TidyCmd = &cobra.Command{
 
 	Use: "tidy [langs...]",
 
-	Short: "add missinad and remove unused modules",
+	Short: "add missing and remove unused modules",
 
 	Long: tidyLong,
 
@@ -0,0 +1,7 @@
+package flags
+
+type ModGetFlagpole struct {
+	Lang string
+}
+
+var ModGetFlags ModGetFlagpole
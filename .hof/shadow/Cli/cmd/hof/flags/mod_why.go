@@ -0,0 +1,8 @@
+package flags
+
+type ModWhyFlagpole struct {
+	Modules bool
+	Lang    string
+}
+
+var ModWhyFlags ModWhyFlagpole
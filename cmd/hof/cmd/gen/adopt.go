@@ -0,0 +1,95 @@
+package cmdgen
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+
+	"github.com/hofstadter-io/hof/lib"
+
+	"github.com/hofstadter-io/hof/cmd/hof/ga"
+)
+
+var adoptLong = `adopt existing files into a generator
+
+Renders the generators without writing output and reports how similar
+each existing file is to its render. Files which already exist have no
+shadow, so gen can only do a lossy 2-way merge with them. Use --accept
+to record the renders as the shadow, after which gen merges your
+changes with diff3 like any other generated file.`
+
+func init() {
+
+	AdoptCmd.Flags().BoolVarP(&(flags.GenAdoptFlags.Accept), "accept", "", false, "Record the renders as the shadow for existing files")
+}
+
+func AdoptRun(args []string) (err error) {
+
+	err = lib.GenAdopt(args, flags.GenPflags, flags.GenAdoptFlags.Accept)
+
+	return err
+}
+
+var AdoptCmd = &cobra.Command{
+
+	Use: "adopt [files...]",
+
+	Short: "adopt existing files into a generator",
+
+	Long: adoptLong,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+
+		ga.SendCommandPath(cmd.CommandPath())
+
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		// Argument Parsing
+
+		err = AdoptRun(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	extra := func(cmd *cobra.Command) bool {
+
+		return false
+	}
+
+	ohelp := AdoptCmd.HelpFunc()
+	ousage := AdoptCmd.UsageFunc()
+	help := func(cmd *cobra.Command, args []string) {
+		if extra(cmd) {
+			return
+		}
+		ohelp(cmd, args)
+	}
+	usage := func(cmd *cobra.Command) error {
+		if extra(cmd) {
+			return nil
+		}
+		return ousage(cmd)
+	}
+
+	thelp := func(cmd *cobra.Command, args []string) {
+		ga.SendCommandPath(cmd.CommandPath() + " help")
+		help(cmd, args)
+	}
+	tusage := func(cmd *cobra.Command) error {
+		ga.SendCommandPath(cmd.CommandPath() + " usage")
+		return usage(cmd)
+	}
+	AdoptCmd.SetHelpFunc(thelp)
+	AdoptCmd.SetUsageFunc(tusage)
+
+}
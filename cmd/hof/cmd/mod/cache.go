@@ -0,0 +1,70 @@
+package cmdmod
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/hofstadter-io/hof/cmd/hof/cmd/mod/cache"
+
+	"github.com/hofstadter-io/hof/cmd/hof/ga"
+)
+
+var cacheLong = `manage the module cache
+
+Modules are cached in <UserConfigDir>/hof/mods,
+set HOF_MOD_CACHE to use another directory.
+Patterns are module paths, which include the modules
+below them, or module@version.`
+
+var CacheCmd = &cobra.Command{
+
+	Use: "cache",
+
+	Short: "manage the module cache",
+
+	Long: cacheLong,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+
+		ga.SendCommandPath(cmd.CommandPath())
+
+	},
+}
+
+func init() {
+	extra := func(cmd *cobra.Command) bool {
+
+		return false
+	}
+
+	ohelp := CacheCmd.HelpFunc()
+	ousage := CacheCmd.UsageFunc()
+	help := func(cmd *cobra.Command, args []string) {
+		if extra(cmd) {
+			return
+		}
+		ohelp(cmd, args)
+	}
+	usage := func(cmd *cobra.Command) error {
+		if extra(cmd) {
+			return nil
+		}
+		return ousage(cmd)
+	}
+
+	thelp := func(cmd *cobra.Command, args []string) {
+		ga.SendCommandPath(cmd.CommandPath() + " help")
+		help(cmd, args)
+	}
+	tusage := func(cmd *cobra.Command) error {
+		ga.SendCommandPath(cmd.CommandPath() + " usage")
+		return usage(cmd)
+	}
+	CacheCmd.SetHelpFunc(thelp)
+	CacheCmd.SetUsageFunc(tusage)
+
+	CacheCmd.AddCommand(cmdcache.ListCmd)
+	CacheCmd.AddCommand(cmdcache.CleanCmd)
+	CacheCmd.AddCommand(cmdcache.VerifyCmd)
+	CacheCmd.AddCommand(cmdcache.PathCmd)
+
+}
@@ -0,0 +1,118 @@
+package cmdmod
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+
+	"github.com/hofstadter-io/hof/lib/mod"
+
+	"github.com/hofstadter-io/hof/cmd/hof/ga"
+)
+
+var getLong = `add or update dependencies
+
+Each argument is module@version, the version may be
+  v1.2.3     an exact version
+  latest     the greatest release, or HEAD when there are no tags
+  upgrade    latest, unless the required version is greater (the default)
+  patch      the greatest release with the same major and minor
+  none       drop the require
+  <branch>   a branch or commit, recorded as a pseudo-version
+
+The mod file is updated, then dependencies are vendored,
+selecting versions with MVS and updating the sum file.`
+
+func init() {
+
+	GetCmd.Flags().StringVarP(&(flags.ModGetFlags.Lang), "lang", "", "", "language of the modules, discovered when there is only one")
+}
+
+func GetRun(modules []string) (err error) {
+
+	err = mod.Get(flags.ModGetFlags.Lang, modules)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return err
+}
+
+var GetCmd = &cobra.Command{
+
+	Use: "get <module@version...>",
+
+	Short: "add or update dependencies",
+
+	Long: getLong,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+
+		ga.SendCommandPath(cmd.CommandPath())
+
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		// Argument Parsing
+
+		if 0 >= len(args) {
+			fmt.Println("missing required argument: 'modules'")
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		var modules []string
+
+		if 0 < len(args) {
+
+			modules = args[0:]
+
+		}
+
+		err = GetRun(modules)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	extra := func(cmd *cobra.Command) bool {
+
+		return false
+	}
+
+	ohelp := GetCmd.HelpFunc()
+	ousage := GetCmd.UsageFunc()
+	help := func(cmd *cobra.Command, args []string) {
+		if extra(cmd) {
+			return
+		}
+		ohelp(cmd, args)
+	}
+	usage := func(cmd *cobra.Command) error {
+		if extra(cmd) {
+			return nil
+		}
+		return ousage(cmd)
+	}
+
+	thelp := func(cmd *cobra.Command, args []string) {
+		ga.SendCommandPath(cmd.CommandPath() + " help")
+		help(cmd, args)
+	}
+	tusage := func(cmd *cobra.Command) error {
+		ga.SendCommandPath(cmd.CommandPath() + " usage")
+		return usage(cmd)
+	}
+	GetCmd.SetHelpFunc(thelp)
+	GetCmd.SetUsageFunc(tusage)
+
+}
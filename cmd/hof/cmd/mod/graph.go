@@ -6,16 +6,28 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+
 	"github.com/hofstadter-io/hof/lib/mod"
 
 	"github.com/hofstadter-io/hof/cmd/hof/ga"
 )
 
-var graphLong = `print module requirement graph`
+var graphLong = `print module requirement graph
+
+Prints each require as 'module@version dep@version', like 'go mod graph',
+including the versions MVS passed over. Replaced modules are shown
+with '=> replacement@version'. Use --format dot for graphviz, where
+the selected versions are bold, or --format json.`
+
+func init() {
+
+	GraphCmd.Flags().StringVarP(&(flags.ModGraphFlags.Format), "format", "", "text", "Output format [text, dot, json]")
+}
 
 func GraphRun(args []string) (err error) {
 
-	err = mod.ProcessLangs("graph", args)
+	err = mod.GraphLangs(args, flags.ModGraphFlags.Format)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -26,7 +38,7 @@ func GraphRun(args []string) (err error) {
 
 var GraphCmd = &cobra.Command{
 
-	Use: "graph",
+	Use: "graph [langs...]",
 
 	Short: "print module requirement graph",
 
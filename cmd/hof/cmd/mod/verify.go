@@ -11,7 +11,12 @@ import (
 	"github.com/hofstadter-io/hof/cmd/hof/ga"
 )
 
-var verifyLong = `verify dependencies have expected content`
+var verifyLong = `verify dependencies have expected content
+
+Dependencies are loaded as for vendoring, and each fetched or cached module
+is hashed and compared to the sum file. A mismatch is a security error,
+the module may have been tampered with. Vendored files must be unchanged
+copies of their module, and local replaces must match their vendored copy.`
 
 func VerifyRun(args []string) (err error) {
 
@@ -0,0 +1,117 @@
+package cmdmod
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+
+	"github.com/hofstadter-io/hof/lib/mod"
+
+	"github.com/hofstadter-io/hof/cmd/hof/ga"
+)
+
+var whyLong = `explain why packages or modules are needed
+
+Prints the shortest chain of requires from the root module
+to the module of each package, through the versions MVS selected,
+like 'go mod why'. With -m the arguments are modules.
+
+  # example.com/c
+  example.com/root
+  example.com/a@v1.2.0
+  example.com/c@v0.3.1`
+
+func init() {
+
+	WhyCmd.Flags().BoolVarP(&(flags.ModWhyFlags.Modules), "modules", "m", false, "the arguments are modules")
+	WhyCmd.Flags().StringVarP(&(flags.ModWhyFlags.Lang), "lang", "", "", "language of the modules, discovered when there is only one")
+}
+
+func WhyRun(packages []string) (err error) {
+
+	err = mod.Why(flags.ModWhyFlags.Lang, packages, flags.ModWhyFlags.Modules)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return err
+}
+
+var WhyCmd = &cobra.Command{
+
+	Use: "why <packages...>",
+
+	Short: "explain why packages or modules are needed",
+
+	Long: whyLong,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+
+		ga.SendCommandPath(cmd.CommandPath())
+
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		// Argument Parsing
+
+		if 0 >= len(args) {
+			fmt.Println("missing required argument: 'packages'")
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		var packages []string
+
+		if 0 < len(args) {
+
+			packages = args[0:]
+
+		}
+
+		err = WhyRun(packages)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	extra := func(cmd *cobra.Command) bool {
+
+		return false
+	}
+
+	ohelp := WhyCmd.HelpFunc()
+	ousage := WhyCmd.UsageFunc()
+	help := func(cmd *cobra.Command, args []string) {
+		if extra(cmd) {
+			return
+		}
+		ohelp(cmd, args)
+	}
+	usage := func(cmd *cobra.Command) error {
+		if extra(cmd) {
+			return nil
+		}
+		return ousage(cmd)
+	}
+
+	thelp := func(cmd *cobra.Command, args []string) {
+		ga.SendCommandPath(cmd.CommandPath() + " help")
+		help(cmd, args)
+	}
+	tusage := func(cmd *cobra.Command) error {
+		ga.SendCommandPath(cmd.CommandPath() + " usage")
+		return usage(cmd)
+	}
+	WhyCmd.SetHelpFunc(thelp)
+	WhyCmd.SetUsageFunc(tusage)
+
+}
@@ -1,8 +1,16 @@
 package flags
 
-type GenFlagpole struct {
-	Stats     bool
-	Generator []string
+type GenPflagpole struct {
+	Stats      bool
+	Generator  []string
+	Tags       []string
+	Set        []string
+	Only       []string
+	Exclude    []string
+	Report     string
+	ReportFile string
+	FailOn     []string
+	BatchSize  int
 }
 
-var GenFlags GenFlagpole
+var GenPflags GenPflagpole
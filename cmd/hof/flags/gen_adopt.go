@@ -0,0 +1,7 @@
+package flags
+
+type GenAdoptFlagpole struct {
+	Accept bool
+}
+
+var GenAdoptFlags GenAdoptFlagpole
@@ -0,0 +1,7 @@
+package flags
+
+type GenServeFlagpole struct {
+	Addr string
+}
+
+var GenServeFlags GenServeFlagpole
@@ -0,0 +1,7 @@
+package flags
+
+type ModPflagpole struct {
+	Offline bool
+}
+
+var ModPflags ModPflagpole
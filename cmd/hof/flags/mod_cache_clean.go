@@ -0,0 +1,8 @@
+package flags
+
+type ModCacheCleanFlagpole struct {
+	All    bool
+	DryRun bool
+}
+
+var ModCacheCleanFlags ModCacheCleanFlagpole
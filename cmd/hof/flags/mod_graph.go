@@ -0,0 +1,7 @@
+package flags
+
+type ModGraphFlagpole struct {
+	Format string
+}
+
+var ModGraphFlags ModGraphFlagpole
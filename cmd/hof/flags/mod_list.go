@@ -0,0 +1,7 @@
+package flags
+
+type ModListFlagpole struct {
+	Updates bool
+}
+
+var ModListFlags ModListFlagpole
@@ -12,9 +12,12 @@ import (
 	Short: "generate code, data, and config from your data models and designs"
 	Long: """
     generate all the things, from code to data to config...
+
+    Generators can read In data from JSON, YAML, CSV, TOML, XML, and SQLite files with InData.
+    SQLite needs the sqlite3 command-line program, version 3.33 or later, on the PATH.
   """
 
-	Flags: [...schema.#Flag] & [
+	Pflags: [...schema.#Flag] & [
 		{
 			Name:    "stats"
 			Type:    "bool"
@@ -31,8 +34,161 @@ import (
 			Long:    "generator"
 			Short:   "g"
 		},
+		{
+			Name:    "tags"
+			Type:    "[]string"
+			Default: "nil"
+			Help:    "Cue tags to inject into @tag() fields, as key=value or short value"
+			Long:    "tags"
+			Short:   "t"
+		},
+		{
+			Name:    "set"
+			Type:    "[]string"
+			Default: "nil"
+			Help:    "Values to fill into open fields at load time, as path[:type]=value, where values are strings unless quoted or typed as int, number, bool, or json"
+			Long:    "set"
+		},
+		{
+			Name:    "only"
+			Type:    "[]string"
+			Default: "nil"
+			Help:    "Only generate output files matching these globs"
+			Long:    "only"
+		},
+		{
+			Name:    "exclude"
+			Type:    "[]string"
+			Default: "nil"
+			Help:    "Do not generate output files matching these globs"
+			Long:    "exclude"
+		},
+		{
+			Name:    "report"
+			Type:    "string"
+			Default: ""
+			Help:    "Write a machine readable report [json, junit]"
+			Long:    "report"
+		},
+		{
+			Name:    "report-file"
+			Type:    "string"
+			Default: ""
+			Help:    "File to write the --report to, it is required with --report"
+			Long:    "report-file"
+		},
+		{
+			Name:    "fail-on"
+			Type:    "[]string"
+			Default: "nil"
+			Help:    "Exit non-zero when output has [conflict, change]"
+			Long:    "fail-on"
+		},
+		{
+			Name:    "batch-size"
+			Type:    "int"
+			Default: "0"
+			Help:    "Decode, render, and write files this many at a time, bounding memory for large generators"
+			Long:    "batch-size"
+		},
 	]
 
+	Commands: [{
+		TBD:   "β"
+		Name:  "adopt"
+		Usage: "adopt [files...]"
+		Short: "adopt existing files into a generator"
+		Long: """
+		adopt existing files into a generator
+
+		Renders the generators without writing output and reports how similar
+		each existing file is to its render. Files which already exist have no
+		shadow, so gen can only do a lossy 2-way merge with them. Use --accept
+		to record the renders as the shadow, after which gen merges your
+		changes with diff3 like any other generated file.
+		"""
+
+		Flags: [...schema.#Flag] & [{
+			Name:    "accept"
+			Type:    "bool"
+			Default: "false"
+			Help:    "Record the renders as the shadow for existing files"
+			Long:    "accept"
+		}]
+
+		Imports: [
+			{Path: "github.com/hofstadter-io/hof/lib", ...},
+		]
+
+		Body: """
+		err = lib.GenAdopt(args, flags.GenPflags, flags.GenAdoptFlags.Accept)
+		"""
+	}, {
+		TBD:   "β"
+		Name:  "eject"
+		Usage: "eject <generator|path...>"
+		Short: "eject files or generators from hof ownership"
+		Long: """
+		eject files or generators from hof ownership
+
+		Targets are generator names or output paths, directories eject all files under them.
+		The shadow is removed and the targets are recorded in .hof/ejected, so the files
+		become plain user files which gen no longer renders, merges, or deletes.
+		A "Code generated ... DO NOT EDIT." first line is removed from the files.
+		Targets without generated files, like a generator which never ran, are a no-op.
+		"""
+
+		Args: [{
+			Name:     "targets"
+			Type:     "[]string"
+			Required: true
+			Rest:     true
+			Help:     "generator names or output paths to eject"
+		}]
+
+		Imports: [
+			{Path: "github.com/hofstadter-io/hof/lib", ...},
+		]
+
+		Body: """
+		err = lib.GenEject(targets)
+		"""
+	}, {
+		TBD:   "α"
+		Name:  "serve"
+		Usage: "serve [files...]"
+		Short: "serve generators over a local HTTP/JSON API"
+		Long: """
+		serve generators over a local HTTP/JSON API
+
+		Keeps the Cue, templates, and shadow loaded between requests,
+		reloading when the Cue, imports, templates, or data change, so editors and hooks can
+		render without the startup cost.
+
+		  GET  /generators                           list generators and their output files
+		  GET  /render?generator=<name>&filepath=<p> render a single file, nothing is written
+		  POST /gen                                  run the generators and write the output
+		  POST /reload                               reload the Cue files
+
+		Only local hosts and pages can reach it, and POSTs must send Content-Type: application/json.
+		"""
+
+		Flags: [...schema.#Flag] & [{
+			Name:    "addr"
+			Type:    "string"
+			Default: "localhost:2323"
+			Help:    "Address to listen on, keep it local"
+			Long:    "addr"
+		}]
+
+		Imports: [
+			{Path: "github.com/hofstadter-io/hof/lib", ...},
+		]
+
+		Body: """
+		err = lib.GenServe(args, flags.GenPflags, flags.GenServeFlags.Addr)
+		"""
+	}]
 }
 
 #FeedbackCommand: schema.#Command & {
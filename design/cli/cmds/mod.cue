@@ -38,9 +38,18 @@ import (
 
 	Imports: #ModCmdImports
 
+	Pflags: [...schema.#Flag] & [{
+		Name:    "offline"
+		Type:    "bool"
+		Default: "false"
+		Help:    "only use the module cache, fail instead of fetching"
+		Long:    "offline"
+	}]
+
 	PersistentPrerun: true
 	PersistentPrerunBody: """
     mod.InitLangs()
+    mod.SetOffline(flags.ModPflags.Offline)
   """
 
 	Commands: [{
@@ -101,16 +110,82 @@ import (
 		"""
 
 	}, {
-		TBD:   "Ø"
+		TBD:   "β"
 		Name:  "graph"
-		Usage: "graph"
+		Usage: "graph [langs...]"
 		Short: "print module requirement graph"
-		Long:  Short
+		Long: """
+		print module requirement graph
+
+		Prints each require as 'module@version dep@version', like 'go mod graph',
+		including the versions MVS passed over. Replaced modules are shown
+		with '=> replacement@version'. Use --format dot for graphviz, where
+		the selected versions are bold, or --format json.
+		"""
+
+		Flags: [...schema.#Flag] & [{
+			Name:    "format"
+			Type:    "string"
+			Default: "text"
+			Help:    "Output format [text, dot, json]"
+			Long:    "format"
+		}]
 
 		Imports: #ModCmdImports
 
 		Body: """
-		err = mod.ProcessLangs("graph", args)
+		err = mod.GraphLangs(args, flags.ModGraphFlags.Format)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		"""
+
+	}, {
+		TBD:   "β"
+		Name:  "why"
+		Usage: "why <packages...>"
+		Short: "explain why packages or modules are needed"
+		Long: """
+		explain why packages or modules are needed
+
+		Prints the shortest chain of requires from the root module
+		to the module of each package, through the versions MVS selected,
+		like 'go mod why'. With -m the arguments are modules.
+
+		  # example.com/c
+		  example.com/root
+		  example.com/a@v1.2.0
+		  example.com/c@v0.3.1
+		"""
+
+		Args: [{
+			Name:     "packages"
+			Type:     "[]string"
+			Required: true
+			Rest:     true
+			Help:     "packages, or modules with -m"
+		}]
+
+		Flags: [...schema.#Flag] & [{
+			Name:    "modules"
+			Type:    "bool"
+			Default: "false"
+			Help:    "the arguments are modules"
+			Long:    "modules"
+			Short:   "m"
+		}, {
+			Name:    "lang"
+			Type:    "string"
+			Default: ""
+			Help:    "language of the modules, discovered when there is only one"
+			Long:    "lang"
+		}]
+
+		Imports: #ModCmdImports
+
+		Body: """
+		err = mod.Why(flags.ModWhyFlags.Lang, packages, flags.ModWhyFlags.Modules)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -164,11 +239,189 @@ import (
 		"""
 
 	}, {
-		TBD:   "Ø"
+		TBD:   "β"
+		Name:  "get"
+		Usage: "get <module@version...>"
+		Short: "add or update dependencies"
+		Long: """
+		add or update dependencies
+
+		Each argument is module@version, the version may be
+		  v1.2.3     an exact version
+		  latest     the greatest release, or HEAD when there are no tags
+		  upgrade    latest, unless the required version is greater (the default)
+		  patch      the greatest release with the same major and minor
+		  none       drop the require
+		  <branch>   a branch or commit, recorded as a pseudo-version
+
+		The mod file is updated, then dependencies are vendored,
+		selecting versions with MVS and updating the sum file.
+		"""
+
+		Args: [{
+			Name:     "modules"
+			Type:     "[]string"
+			Required: true
+			Rest:     true
+			Help:     "module@version queries"
+		}]
+
+		Flags: [...schema.#Flag] & [{
+			Name:    "lang"
+			Type:    "string"
+			Default: ""
+			Help:    "language of the modules, discovered when there is only one"
+			Long:    "lang"
+		}]
+
+		Imports: #ModCmdImports
+
+		Body: """
+		err = mod.Get(flags.ModGetFlags.Lang, modules)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		"""
+
+	}, {
+		TBD:   "β"
+		Name:  "list"
+		Usage: "list [langs...]"
+		Short: "list the selected module versions"
+		Long: """
+		list the selected module versions
+
+		Prints the main module and then each dependency as
+		'module version [=> replacement version]', like 'go list -m all'.
+		With -u, the latest version is shown in brackets when it is greater.
+		"""
+
+		Flags: [...schema.#Flag] & [{
+			Name:    "updates"
+			Type:    "bool"
+			Default: "false"
+			Help:    "show available upgrades"
+			Long:    "updates"
+			Short:   "u"
+		}]
+
+		Imports: #ModCmdImports
+
+		Body: """
+		err = mod.ListLangs(args, flags.ModListFlags.Updates)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		"""
+
+	}, {
+		TBD:   "β"
+		Name:  "cache"
+		Usage: "cache"
+		Short: "manage the module cache"
+		Long: """
+		manage the module cache
+
+		Modules are cached in <UserConfigDir>/hof/mods,
+		set HOF_MOD_CACHE to use another directory.
+		Patterns are module paths, which include the modules
+		below them, or module@version.
+		"""
+
+		OmitRun: true
+
+		Commands: [{
+			TBD:   "β"
+			Name:  "list"
+			Usage: "list [patterns...]"
+			Short: "list cached modules and their sizes"
+			Long:  Short
+
+			Imports: #ModCmdImports
+
+			Body: """
+			err = mod.CacheList(args)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			"""
+		}, {
+			TBD:   "β"
+			Name:  "clean"
+			Usage: "clean [patterns...]"
+			Short: "remove cached modules, matching the patterns or --all of them"
+			Long:  Short
+
+			Flags: [...schema.#Flag] & [{
+				Name:    "all"
+				Type:    "bool"
+				Default: "false"
+				Help:    "remove every cached module"
+				Long:    "all"
+			}, {
+				Name:    "dry-run"
+				Type:    "bool"
+				Default: "false"
+				Help:    "print what would be removed"
+				Long:    "dry-run"
+			}]
+
+			Imports: #ModCmdImports
+
+			Body: """
+			err = mod.CacheClean(args, flags.ModCacheCleanFlags.All, flags.ModCacheCleanFlags.DryRun)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			"""
+		}, {
+			TBD:   "β"
+			Name:  "verify"
+			Usage: "verify [patterns...]"
+			Short: "check cached modules are unchanged since they were fetched"
+			Long:  Short
+
+			Imports: #ModCmdImports
+
+			Body: """
+			err = mod.CacheVerify(args)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			"""
+		}, {
+			TBD:   "β"
+			Name:  "path"
+			Usage: "path"
+			Short: "print the module cache directory"
+			Long:  Short
+
+			Imports: #ModCmdImports
+
+			Body: """
+			err = mod.CachePath()
+			"""
+		}]
+
+	}, {
+		TBD:   "β"
 		Name:  "tidy"
 		Usage: "tidy [langs...]"
-		Short: "add missinad and remove unused modules"
-		Long:  Short
+		Short: "add missing and remove unused modules"
+		Long: """
+		add missing and remove unused modules
+
+		Imports are discovered by scanning the module's files with the
+		Introspect globs and regexes from the language's modder config.
+		Missing modules are required at their latest version, requires
+		which are no longer imported are dropped, and the sum file is
+		rewritten for the resolved dependencies.
+		"""
 
 		Imports: #ModCmdImports
 
@@ -198,11 +451,18 @@ import (
 		"""
 
 	}, {
-		TBD:   "Ø"
+		TBD:   "β"
 		Name:  "verify"
 		Usage: "verify [langs...]"
 		Short: "verify dependencies have expected content"
-		Long:  Short
+		Long: """
+		verify dependencies have expected content
+
+		Dependencies are loaded as for vendoring, and each fetched or cached module
+		is hashed and compared to the sum file. A mismatch is a security error,
+		the module may have been tampered with. Vendored files must be unchanged
+		copies of their module, and local replaces must match their vendored copy.
+		"""
 
 		Imports: #ModCmdImports
 
@@ -310,8 +570,10 @@ import (
 	# Required dependencies section
 	require (
 		# <module-path> <module-semver>
-		github.com/hof-lang/cuemod--cli-golang v0.0.0      # This is latest on HEAD
+		github.com/hof-lang/cuemod--cli-golang v0.0.0      # This is HEAD on the default branch, recorded as a pseudo-version
 		github.com/hof-lang/cuemod--cli-golang v0.1.5      # This is a tag v0.1.5 (can omit 'v' in tag, but not here)
+		github.com/hof-lang/cuemod--cli-golang main        # A branch or commit, also recorded as a pseudo-version
+		github.com/hof-lang/cuemod--cli-golang v0.1.6-0.20200601000000-abcdef123456  # A pseudo-version, an untagged commit
 	)
 
 	# replace <module-path> => <module-path|local-path> [version]
@@ -492,6 +754,9 @@ import (
 		NoLoad: false
 		CommandInit: [[string]]
 		CommandGraph: [[string]]
+		CommandGet: [[string]]
+		CommandList: [[string]]
+		CommandWhy: [[string]]
 		CommandTidy: [[string]]
 		CommandVendor: [[string]]
 		CommandVerify: [[string]]
@@ -0,0 +1,205 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+	"github.com/hofstadter-io/hof/lib/cuetils"
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+// Adoption status for a generator file
+const (
+	ADOPT_NEW      = "new"      // no existing file, gen will create it
+	ADOPT_SHADOWED = "shadowed" // already has a shadow, gen will merge it
+	ADOPT_SAME     = "same"     // existing file matches the render
+	ADOPT_DIFFERS  = "differs"  // existing file differs from the render
+)
+
+type AdoptResult struct {
+	Generator  string
+	Filepath   string
+	Status     string
+	Similarity float64
+	Adopted    bool
+}
+
+// GenAdopt renders generators without writing output and compares
+// the render to existing files. With accept, the render is recorded as the
+// shadow for existing files, so that later runs merge user changes with diff3
+func GenAdopt(args []string, cmdflags flags.GenPflagpole, accept bool) error {
+	// every file is compared, so there is no streaming
+	cmdflags.BatchSize = 0
+	R := NewRuntime(args, cmdflags)
+
+	errs := R.LoadCue()
+	if len(errs) > 0 {
+		for _, e := range errs {
+			cuetils.PrintCueError(e)
+		}
+		return fmt.Errorf("\nErrors while loading cue files\n")
+	}
+
+	errs = R.LoadGenerators()
+	if len(errs) > 0 {
+		for _, e := range errs {
+			cuetils.PrintCueError(e)
+		}
+		return fmt.Errorf("\nErrors while loading generators\n")
+	}
+
+	results, errs := R.AdoptGenerators(accept)
+
+	pending := false
+	for _, r := range results {
+		if !r.Adopted && (r.Status == ADOPT_DIFFERS || r.Status == ADOPT_SAME) {
+			pending = true
+		}
+
+		sim := "-"
+		if r.Status == ADOPT_DIFFERS || r.Status == ADOPT_SAME {
+			sim = fmt.Sprintf("%3.0f%%", r.Similarity*100)
+		}
+		adopted := ""
+		if r.Adopted {
+			adopted = "adopted"
+		}
+		fmt.Printf("%-9s %5s  %-8s %s\n", r.Status, sim, adopted, r.Filepath)
+	}
+
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		return fmt.Errorf("\nErrors while adopting files\n")
+	}
+
+	if pending {
+		fmt.Println("\nrun with --accept to record the renders as the shadow for existing files")
+	}
+
+	return nil
+}
+
+func (R *Runtime) AdoptGenerators(accept bool) ([]AdoptResult, []error) {
+	var errs []error
+	var results []AdoptResult
+
+	for _, G := range R.Ordered {
+		if G.Disabled {
+			continue
+		}
+
+		errsS := G.InitStaticFiles()
+		if len(errsS) > 0 {
+			errs = append(errs, errsS...)
+			continue
+		}
+
+		err := G.FilterFiles(R.fileFilter())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		G.RemoveEjected(R.Ejected)
+
+		shadow, err := gen.LoadShadow(G.Name, R.verbose)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		fps := make([]string, 0, len(G.Files))
+		for fp, _ := range G.Files {
+			if fp != "" {
+				fps = append(fps, fp)
+			}
+		}
+		sort.Strings(fps)
+
+		for _, fp := range fps {
+			F := G.Files[fp]
+			res, err := adoptFile(G, F, shadow, accept)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s\n%w", G.Name, fp, err))
+				continue
+			}
+			results = append(results, res)
+		}
+	}
+
+	return results, errs
+}
+
+func adoptFile(G *gen.Generator, F *gen.File, shadow map[string]*gen.File, accept bool) (AdoptResult, error) {
+	res := AdoptResult{
+		Generator: G.Name,
+		Filepath:  F.Filepath,
+	}
+
+	if _, ok := shadow[path.Join(G.Name, F.Filepath)]; ok {
+		res.Status = ADOPT_SHADOWED
+		return res, nil
+	}
+
+	_, err := os.Lstat(F.Filepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			res.Status = ADOPT_NEW
+			return res, nil
+		}
+		return res, err
+	}
+
+	err = F.RenderTemplate()
+	if err != nil {
+		return res, err
+	}
+
+	err = F.ReadUser()
+	if err != nil {
+		return res, err
+	}
+
+	res.Similarity = similarity(F.RenderContent, F.UserFile.FinalContent, F.IsRaw())
+	res.Status = ADOPT_DIFFERS
+	if res.Similarity == 1.0 {
+		res.Status = ADOPT_SAME
+	}
+
+	if accept {
+		err = F.WriteShadow(path.Join(gen.SHADOW_DIR, G.Name))
+		if err != nil {
+			return res, err
+		}
+		res.Adopted = true
+	}
+
+	return res, nil
+}
+
+// similarity is 1 minus the edit distance relative to the longer content
+func similarity(a, b []byte, raw bool) float64 {
+	if string(a) == string(b) {
+		return 1.0
+	}
+	if raw {
+		return 0.0
+	}
+
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMain(string(a), string(b), false)
+	dist := dmp.DiffLevenshtein(diffs)
+
+	max := len([]rune(string(a)))
+	if l := len([]rune(string(b))); l > max {
+		max = l
+	}
+
+	return 1.0 - float64(dist)/float64(max)
+}
@@ -0,0 +1,90 @@
+package lib
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+func TestSimilarity(t *testing.T) {
+	cases := []struct {
+		A, B   string
+		Raw    bool
+		Expect float64
+	}{
+		{"same", "same", false, 1.0},
+		{"abcd", "abce", false, 0.75},
+		{"abcd", "", false, 0.0},
+		{"abcd", "abcdefgh", false, 0.5},
+		{"\x00\x01", "\x00\x02", true, 0.0},
+		{"\x00\x01", "\x00\x01", true, 1.0},
+	}
+
+	for _, c := range cases {
+		assert.InDelta(t, c.Expect, similarity([]byte(c.A), []byte(c.B), c.Raw), 0.001, "%q %q", c.A, c.B)
+	}
+}
+
+func TestAdoptFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hof-adopt-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	for fn, content := range map[string]string{
+		"same.txt":                   "content",
+		"differs.txt":                "contents",
+		".hof/shadow/G/shadowed.txt": "content",
+	} {
+		os.MkdirAll(filepath.Dir(fn), 0755)
+		ioutil.WriteFile(fn, []byte(content), 0644)
+	}
+
+	G := gen.NewGenerator("G", cue.Value{})
+	shadow, err := gen.LoadShadow(G.Name, false)
+	assert.NoError(t, err)
+
+	adopt := func(fp string, accept bool) AdoptResult {
+		F := &gen.File{Filepath: fp, RenderContent: []byte("content")}
+		F.IsStatic = 1
+		res, err := adoptFile(G, F, shadow, accept)
+		assert.NoError(t, err)
+		return res
+	}
+
+	assert.Equal(t, ADOPT_NEW, adopt("new.txt", true).Status)
+	assert.Equal(t, ADOPT_SHADOWED, adopt("shadowed.txt", true).Status)
+
+	res := adopt("same.txt", false)
+	assert.Equal(t, ADOPT_SAME, res.Status)
+	assert.False(t, res.Adopted)
+
+	res = adopt("differs.txt", true)
+	assert.Equal(t, ADOPT_DIFFERS, res.Status)
+	assert.InDelta(t, 0.875, res.Similarity, 0.001)
+	assert.True(t, res.Adopted)
+
+	// the render is the shadow, the user's file is untouched
+	content, err := ioutil.ReadFile(".hof/shadow/G/differs.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+	content, err = ioutil.ReadFile("differs.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "contents", string(content))
+
+	// without accept, nothing is written
+	_, err = os.Stat(".hof/shadow/G/same.txt")
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(".hof/shadow/G/new.txt")
+	assert.True(t, os.IsNotExist(err))
+}
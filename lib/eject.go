@@ -0,0 +1,104 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+// GenEject removes files from hof ownership, targets are generator names or
+// output paths (files or directories). Their shadow is removed and they are
+// recorded as ejected, so gen no longer renders, merges, or deletes them.
+// Any "Code generated ... DO NOT EDIT." header is removed from the files.
+// Hof does not keep a manifest, the shadow is the record of ownership.
+// Targets without generated files, like a generator which never ran, are a no-op.
+func GenEject(targets []string) error {
+	shadow, err := gen.LoadShadow("", false)
+	if err != nil {
+		return err
+	}
+
+	ejected, err := gen.LoadEjected()
+	if err != nil {
+		return err
+	}
+
+	// shadow keys are "<generator>/<filepath>"
+	keys := make([]string, 0, len(shadow))
+	for key, _ := range shadow {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, target := range targets {
+		target = path.Clean(target)
+		found := false
+
+		// a whole generator
+		if info, err := os.Stat(path.Join(gen.SHADOW_DIR, target)); err == nil && info.IsDir() && !strings.Contains(target, "/") {
+			for _, key := range keys {
+				if strings.HasPrefix(key, target+"/") {
+					err := ejectHeader(key[len(target)+1:])
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			err = os.RemoveAll(path.Join(gen.SHADOW_DIR, target))
+			if err != nil {
+				return err
+			}
+			ejected[target] = true
+			fmt.Printf("ejected generator %s\n", target)
+			continue
+		}
+
+		// files, or directories of files
+		for _, key := range keys {
+			pos := strings.Index(key, "/")
+			if pos < 0 {
+				continue
+			}
+			fp := key[pos+1:]
+			if fp != target && !strings.HasPrefix(fp, target+"/") {
+				continue
+			}
+
+			err := ejectHeader(fp)
+			if err != nil {
+				return err
+			}
+
+			err = os.Remove(filepath.Join(gen.SHADOW_DIR, key))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			ejected[key] = true
+			found = true
+			fmt.Printf("ejected %s from %s\n", fp, key[:pos])
+		}
+
+		if !found {
+			fmt.Printf("nothing to eject for %s, there are no generated files\n", target)
+		}
+	}
+
+	return gen.WriteEjected(ejected)
+}
+
+func ejectHeader(fp string) error {
+	stripped, err := gen.StripGeneratedHeader(fp)
+	if err != nil {
+		return fmt.Errorf("while removing the generated header from %q\n%w", fp, err)
+	}
+	if stripped {
+		fmt.Printf("removed the generated header from %s\n", fp)
+	}
+	return nil
+}
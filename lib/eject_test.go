@@ -0,0 +1,72 @@
+package lib
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+func TestGenEject(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hof-eject-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	header := "// Code generated by hof. DO NOT EDIT.\n"
+	for fn, content := range map[string]string{
+		"a/one.go":                   header + "package a\n",
+		"a/sub/two.go":               header + "package sub\n",
+		"b/three.go":                 header + "package b\n",
+		"c/four.go":                  header + "package c\n",
+		".hof/shadow/A/a/one.go":     header + "package a\n",
+		".hof/shadow/A/a/sub/two.go": header + "package sub\n",
+		".hof/shadow/A/b/three.go":   header + "package b\n",
+		".hof/shadow/C/c/four.go":    header + "package c\n",
+	} {
+		os.MkdirAll(filepath.Dir(fn), 0755)
+		ioutil.WriteFile(fn, []byte(content), 0644)
+	}
+
+	exists := func(fn string) bool {
+		_, err := os.Lstat(fn)
+		return err == nil
+	}
+	read := func(fn string) string {
+		content, _ := ioutil.ReadFile(fn)
+		return string(content)
+	}
+
+	// a directory, a file, and a generator which never ran
+	err = GenEject([]string{"a/sub", "b/three.go", "Never"})
+	assert.NoError(t, err)
+
+	assert.False(t, exists(".hof/shadow/A/a/sub/two.go"))
+	assert.False(t, exists(".hof/shadow/A/b/three.go"))
+	assert.True(t, exists(".hof/shadow/A/a/one.go"))
+	assert.Equal(t, "package sub\n", read("a/sub/two.go"))
+	assert.Equal(t, "package b\n", read("b/three.go"))
+	assert.Equal(t, header+"package a\n", read("a/one.go"))
+
+	// a whole generator
+	err = GenEject([]string{"C"})
+	assert.NoError(t, err)
+	assert.False(t, exists(".hof/shadow/C"))
+	assert.Equal(t, "package c\n", read("c/four.go"))
+
+	ejected, err := gen.LoadEjected()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{
+		"A/a/sub/two.go": true,
+		"A/b/three.go":   true,
+		"C":              true,
+	}, ejected)
+}
@@ -8,10 +8,16 @@ import (
 	"github.com/hofstadter-io/hof/lib/cuetils"
 )
 
-func Gen(args []string, cmdflags flags.GenFlagpole) (error) {
+func Gen(args []string, cmdflags flags.GenPflagpole) (error) {
 
 	verystart := time.Now()
 
+	// bad flags should not cost a run, or leave output without its report
+	err := CheckReportFlags(cmdflags)
+	if err != nil {
+		return err
+	}
+
 	var errs []error
 
 	R := NewRuntime(args, cmdflags)
@@ -21,6 +27,7 @@ func Gen(args []string, cmdflags flags.GenFlagpole) (error) {
 		for _, e := range errs {
 			cuetils.PrintCueError(e)
 		}
+		R.reportLoadErrors(errs, verystart)
 		return fmt.Errorf("\nErrors while loading cue files\n")
 	}
 
@@ -29,19 +36,26 @@ func Gen(args []string, cmdflags flags.GenFlagpole) (error) {
 		for _, e := range errsL {
 			cuetils.PrintCueError(e)
 		}
+		R.reportLoadErrors(errsL, verystart)
 		return fmt.Errorf("\nErrors while loading generators\n")
 	}
 
 	// issue #20 - Don't print and exit on error here, wait until after we have written, so we can still write good files
-	errsG := R.RunGenerators()
+	errsG, errsW := R.RunGenerators()
 	// fmt.Println("errsG", errsG)
-	errsW := R.WriteOutput()
+	errsO := R.WriteOutput()
+	errsW = append(errsW, errsO...)
 	// fmt.Println("errsW", errsW)
 
 	// final timing
 	veryend := time.Now()
 	elapsed := veryend.Sub(verystart).Round(time.Millisecond)
 
+	// generator and file errors are in the report already
+	err = R.WriteReport(errsO, elapsed)
+	if err != nil {
+		return err
+	}
 
 	if cmdflags.Stats {
 		R.PrintStats()
@@ -63,7 +77,16 @@ func Gen(args []string, cmdflags flags.GenFlagpole) (error) {
 
 	R.PrintMergeConflicts()
 
-	return nil
+	return R.CheckPolicy()
+}
+
+// reportLoadErrors still writes the report, so CI can annotate Cue errors
+func (R *Runtime) reportLoadErrors(errs []error, start time.Time) {
+	elapsed := time.Now().Sub(start).Round(time.Millisecond)
+	err := R.WriteReport(errs, elapsed)
+	if err != nil {
+		fmt.Println(err)
+	}
 }
 
 
@@ -0,0 +1,124 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+)
+
+// Default permissions for written files
+const DEFAULT_FILE_MODE os.FileMode = 0644
+
+// Raw files, binary content or symlinks, are not rendered or merged
+func (F *File) IsRaw() bool {
+	return F.IsBinary || F.Symlink != ""
+}
+
+func (F *File) FileMode() os.FileMode {
+	if F.Mode == 0 {
+		return DEFAULT_FILE_MODE
+	}
+	return F.Mode
+}
+
+// LoadContent fills the render content of raw files,
+// Source file references are relative to basedir
+func (F *File) LoadContent(basedir string) error {
+	if F.Base64 != "" && F.Source != "" {
+		return fmt.Errorf("Cannot specify both Base64 and Source for file %q", F.Filepath)
+	}
+
+	switch {
+	case F.Symlink != "":
+		if F.Base64 != "" || F.Source != "" {
+			return fmt.Errorf("Cannot specify content and Symlink for file %q", F.Filepath)
+		}
+		// we compare and shadow symlinks by their target
+		F.RenderContent = []byte(F.Symlink)
+
+	case F.Base64 != "":
+		data, err := base64.StdEncoding.DecodeString(F.Base64)
+		if err != nil {
+			return fmt.Errorf("while decoding base64 content for file %q\n%w", F.Filepath, err)
+		}
+		F.IsBinary = true
+		F.RenderContent = data
+
+	case F.Source != "":
+		data, err := ioutil.ReadFile(path.Join(basedir, F.Source))
+		if err != nil {
+			return fmt.Errorf("while reading source for file %q\n%w", F.Filepath, err)
+		}
+		F.IsBinary = true
+		F.RenderContent = data
+	}
+
+	return nil
+}
+
+// decodeFileContent reads the output parameters shared by generator and static files
+func decodeFileContent(F *File, file map[string]interface{}) {
+	F.Symlink, _ = file["Symlink"].(string)
+	F.Base64, _ = file["Base64"].(string)
+	F.Source, _ = file["Source"].(string)
+	if mode, ok := file["Mode"].(float64); ok {
+		F.Mode = os.FileMode(mode)
+	}
+}
+
+// readFileOrLink returns the file content, or the target for symlinks
+func readFileOrLink(fn string) ([]byte, error) {
+	info, err := os.Lstat(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(fn)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(target), nil
+	}
+
+	return ioutil.ReadFile(fn)
+}
+
+// unifyRaw decides what to do with raw files, there is no merging,
+// so user modifications are kept and reported as a conflict
+func (F *File) unifyRaw() (write bool, err error) {
+	F.FinalContent = F.RenderContent
+
+	if F.UserFile == nil {
+		F.IsNew = 1
+		return true, nil
+	}
+
+	user := F.UserFile.FinalContent
+	if bytes.Equal(user, F.RenderContent) {
+		F.IsSame = 1
+		return false, nil
+	}
+
+	// the render did not change, keep the user's modifications
+	if F.ShadowFile != nil && bytes.Equal(F.RenderContent, F.ShadowFile.FinalContent) {
+		F.IsSame = 1
+		F.FinalContent = user
+		return false, nil
+	}
+
+	// no user modifications, update the file
+	if F.ShadowFile != nil && bytes.Equal(user, F.ShadowFile.FinalContent) {
+		F.IsModified = 1
+		F.IsModifiedRender = 1
+		return true, nil
+	}
+
+	F.IsModified = 1
+	F.IsConflicted = 1
+	F.FinalContent = user
+	return false, nil
+}
@@ -0,0 +1,91 @@
+package gen_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+type rawCase struct {
+	Name   string
+	Render string
+	Shadow string // empty for no shadow
+	User   string // empty for no user file
+
+	Write bool
+	Final string
+	Stats gen.FileStats
+}
+
+var rawCases = []rawCase{
+	{"new", "a", "", "", true, "a", gen.FileStats{IsNew: 1}},
+	{"same", "a", "a", "a", false, "a", gen.FileStats{IsSame: 1}},
+	{"updated", "b", "a", "a", true, "b", gen.FileStats{IsModified: 1, IsModifiedRender: 1}},
+	{"user-modified", "a", "a", "u", false, "u", gen.FileStats{IsSame: 1}},
+	{"conflict", "b", "a", "u", false, "u", gen.FileStats{IsModified: 1, IsConflicted: 1}},
+}
+
+func TestUnifyRaw(t *testing.T) {
+	for _, c := range rawCases {
+		t.Run(c.Name, func(t *testing.T) {
+			F := &gen.File{
+				Filepath:      "out.bin",
+				IsBinary:      true,
+				RenderContent: []byte(c.Render),
+			}
+			if c.Shadow != "" {
+				F.ShadowFile = &gen.File{FinalContent: []byte(c.Shadow)}
+			}
+			if c.User != "" {
+				F.UserFile = &gen.File{FinalContent: []byte(c.User)}
+			}
+
+			write, err := F.UnifyContent()
+			assert.NoError(t, err)
+			assert.Equal(t, c.Write, write)
+			assert.Equal(t, c.Final, string(F.FinalContent))
+			assert.Equal(t, c.Stats, F.FileStats)
+		})
+	}
+}
+
+func TestWriteMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hof-gen-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "run.sh")
+	if err := ioutil.WriteFile(fn, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	mode := func() os.FileMode {
+		info, err := os.Stat(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.Mode().Perm()
+	}
+
+	// no declared mode, the user's permissions are kept
+	F := &gen.File{Filepath: fn, FinalContent: []byte("new")}
+	assert.NoError(t, F.WriteOutput())
+	assert.NoError(t, F.ApplyMode())
+	assert.Equal(t, os.FileMode(0755), mode())
+
+	// a declared mode is applied, with or without writing
+	F.Mode = 0700
+	assert.NoError(t, F.WriteOutput())
+	assert.Equal(t, os.FileMode(0700), mode())
+
+	os.Chmod(fn, 0644)
+	assert.NoError(t, F.ApplyMode())
+	assert.Equal(t, os.FileMode(0700), mode())
+}
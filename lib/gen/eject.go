@@ -0,0 +1,121 @@
+package gen
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Ejected files and generators are no longer owned by hof,
+// one per line, as a generator name or its shadow path "<generator>/<filepath>"
+const EJECTED_FILE = ".hof/ejected"
+
+func LoadEjected() (map[string]bool, error) {
+	ejected := map[string]bool{}
+
+	f, err := os.Open(EJECTED_FILE)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ejected, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ejected[line] = true
+	}
+
+	return ejected, scanner.Err()
+}
+
+func WriteEjected(ejected map[string]bool) error {
+	lines := make([]string, 0, len(ejected))
+	for e, _ := range ejected {
+		lines = append(lines, e)
+	}
+	sort.Strings(lines)
+
+	content := "# files and generators ejected from hof, see 'hof gen eject'\n"
+	content += strings.Join(lines, "\n") + "\n"
+
+	err := os.MkdirAll(path.Dir(EJECTED_FILE), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(EJECTED_FILE, []byte(content), 0644)
+}
+
+// RemoveEjected drops ejected files so they are not rendered, merged, or deleted.
+// The generator is disabled when it was ejected as a whole.
+func (G *Generator) RemoveEjected(ejected map[string]bool) {
+	if ejected[G.Name] {
+		G.Disabled = true
+		return
+	}
+
+	for fp, _ := range G.Files {
+		if ejected[path.Join(G.Name, fp)] {
+			delete(G.Files, fp)
+		}
+	}
+
+	G.filterStreamPaths(func(fp string) (bool, error) {
+		return !ejected[path.Join(G.Name, fp)], nil
+	})
+}
+
+// The Go convention for marking generated files, with // or # comments
+var generatedHeader = regexp.MustCompile(`^(//|#) Code generated .* DO NOT EDIT\.$`)
+
+// StripGeneratedHeader removes a "Code generated ... DO NOT EDIT." line from the
+// start of a file, after any #! line, so tools treat it as a plain user file.
+// Missing files, symlinks, and files without the header are left alone.
+func StripGeneratedHeader(fn string) (bool, error) {
+	info, err := os.Lstat(fn)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !info.Mode().IsRegular() {
+		return false, nil
+	}
+
+	content, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return false, err
+	}
+
+	start := 0
+	if bytes.HasPrefix(content, []byte("#!")) {
+		pos := bytes.IndexByte(content, '\n')
+		if pos < 0 {
+			return false, nil
+		}
+		start = pos + 1
+	}
+
+	end := len(content)
+	if pos := bytes.IndexByte(content[start:], '\n'); pos >= 0 {
+		end = start + pos + 1
+	}
+	line := strings.TrimRight(string(content[start:end]), "\r\n")
+	if !generatedHeader.MatchString(line) {
+		return false, nil
+	}
+
+	stripped := append(content[:start:start], content[end:]...)
+	return true, ioutil.WriteFile(fn, stripped, info.Mode().Perm())
+}
@@ -0,0 +1,57 @@
+package gen_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+func TestStripGeneratedHeader(t *testing.T) {
+	defer inTempDir(t)()
+
+	cases := []struct {
+		Content  string
+		Expect   string
+		Stripped bool
+	}{
+		{"// Code generated by hof. DO NOT EDIT.\npackage a\n", "package a\n", true},
+		{"# Code generated by hof. DO NOT EDIT.\nkey: val\n", "key: val\n", true},
+		{"#!/bin/sh\n# Code generated by hof. DO NOT EDIT.\necho\n", "#!/bin/sh\necho\n", true},
+		{"// Code generated by hof. DO NOT EDIT.", "", true},
+		{"package a\n// Code generated by hof. DO NOT EDIT.\n", "package a\n// Code generated by hof. DO NOT EDIT.\n", false},
+		{"// Code generated by hof.\npackage a\n", "// Code generated by hof.\npackage a\n", false},
+	}
+
+	for _, c := range cases {
+		writeFiles(t, map[string]string{"file": c.Content})
+
+		stripped, err := gen.StripGeneratedHeader("file")
+		assert.NoError(t, err)
+		assert.Equal(t, c.Stripped, stripped, c.Content)
+
+		content, err := ioutil.ReadFile("file")
+		assert.NoError(t, err)
+		assert.Equal(t, c.Expect, string(content))
+	}
+
+	stripped, err := gen.StripGeneratedHeader("missing")
+	assert.NoError(t, err)
+	assert.False(t, stripped)
+}
+
+func TestRemoveEjected(t *testing.T) {
+	G := gen.NewGenerator("G", cue.Value{})
+	G.Files["out/a.txt"] = &gen.File{Filepath: "out/a.txt"}
+	G.Files["out/b.txt"] = &gen.File{Filepath: "out/b.txt"}
+
+	G.RemoveEjected(map[string]bool{"G/out/a.txt": true, "Other/out/b.txt": true})
+	assert.False(t, G.Disabled)
+	assert.Equal(t, []string{"out/b.txt"}, G.OutputPaths())
+
+	G.RemoveEjected(map[string]bool{"G": true})
+	assert.True(t, G.Disabled)
+}
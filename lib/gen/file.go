@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 
+	"cuelang.org/go/cue"
 	"github.com/epiclabs-io/diff3"
 	"github.com/sergi/go-diff/diffmatchpatch"
 
@@ -22,10 +23,23 @@ type File struct {
   // empty implies don't generate, even though it may endup in the list
 	Filepath     string
 
+	// Output file parameters
+	Mode     os.FileMode // permissions, 0 creates with 0644 and keeps the user's
+	Symlink  string      // when set, the output is a symlink to this target
+	Base64   string      // binary content, base64 encoded
+	Source   string      // binary content, copied from this file
+	IsBinary bool        // set by hof when the content is binary
+
 	// Template parameters
 	TemplateSystem string  // which system ['text/template'(default), 'mustache']
 	Template       string  // The content, takes precedence over next option
 	TemplateName   string  // Named template
+	Layout         string  // Named layout to render the template within
+
+	// Data file parameters, for the 'data' template system
+	DataFormat string      // output format ['json', 'yaml', 'toml', 'cue', 'xml'], empty infers from Filepath
+	Value      interface{} // the value to serialize, defaults to In
+	DataValue  cue.Value   // Value or In as Cue, which keeps the field order
 
   // Template delimiters
 	TemplateConfig *templates.Config
@@ -119,7 +133,7 @@ func (F *File) ReadUser() error {
 		return nil
 	}
 
-	content, err := ioutil.ReadFile(F.Filepath)
+	content, err := readFileOrLink(F.Filepath)
 	if err != nil {
 		return err
 	}
@@ -133,6 +147,11 @@ func (F *File) ReadUser() error {
 }
 
 func (F *File) UnifyContent() (write bool, err error) {
+	// binary and symlinks are not merged
+	if F.IsRaw() {
+		return F.unifyRaw()
+	}
+
 	// set this first, possible change later in this function
 	F.FinalContent = F.RenderContent
 
@@ -219,7 +238,20 @@ func (F *File) UnifyContent() (write bool, err error) {
 func (F *File) RenderTemplate() error {
 	var err error
 
-	F.RenderContent, err = F.TemplateInstance.Render(F.In)
+	// content was loaded when the file was resolved
+	if F.IsRaw() || F.IsStatic > 0 {
+		return nil
+	}
+
+	var data interface{} = F.In
+	if F.Value != nil {
+		data = F.Value
+	}
+	if F.DataValue.Exists() {
+		data = F.DataValue
+	}
+
+	F.RenderContent, err = F.TemplateInstance.Render(data)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,96 @@
+package gen
+
+import (
+	"path"
+	"strings"
+
+	"github.com/mattn/go-zglob"
+)
+
+// A FileFilter selects generator output by filepath globs
+type FileFilter struct {
+	Only    []string
+	Exclude []string
+}
+
+func (FF FileFilter) IsEmpty() bool {
+	return len(FF.Only) == 0 && len(FF.Exclude) == 0
+}
+
+// Match reports if the output filepath is selected
+func (FF FileFilter) Match(fp string) (bool, error) {
+	fp = path.Clean(fp)
+
+	if len(FF.Only) > 0 {
+		found := false
+		for _, glob := range FF.Only {
+			match, err := zglob.Match(path.Clean(glob), fp)
+			if err != nil {
+				return false, err
+			}
+			if match {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	for _, glob := range FF.Exclude {
+		match, err := zglob.Match(path.Clean(glob), fp)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// FilterFiles removes the files which are not selected, so they are not rendered
+func (G *Generator) FilterFiles(FF FileFilter) error {
+	if FF.IsEmpty() {
+		return nil
+	}
+	G.Filter = FF
+
+	for fp, F := range G.Files {
+		if fp == "" {
+			continue
+		}
+		match, err := FF.Match(F.Filepath)
+		if err != nil {
+			return err
+		}
+		if !match {
+			delete(G.Files, fp)
+		}
+	}
+
+	return G.filterStreamPaths(FF.Match)
+}
+
+// FilterShadow removes unselected files from the shadow,
+// so they are not mistaken for orphans and deleted
+func (G *Generator) FilterShadow() error {
+	if G.Filter.IsEmpty() {
+		return nil
+	}
+
+	for key, _ := range G.Shadow {
+		fp := strings.TrimPrefix(key, G.Name+"/")
+		match, err := G.Filter.Match(fp)
+		if err != nil {
+			return err
+		}
+		if !match {
+			delete(G.Shadow, key)
+		}
+	}
+
+	return nil
+}
@@ -25,6 +25,9 @@ type Generator struct {
   // "Global" input, merged with out replacing onto the files
 	In map[string]interface{}
 
+  // Data files outside of Cue, loaded into In under their names
+  InData map[string]*DataSource
+
   // The list fo files for hof to generate, in cue values
 	Out []map[string]interface{}
 
@@ -35,6 +38,10 @@ type Generator struct {
   // Subgenerators for composition
   Generators []*Generator
 
+  // Names of generators which must run before this one,
+  // i.e. when this generator consumes their output
+  DependsOn []string
+
   // Template delimiters
 	TemplateConfig *templates.Config
 
@@ -42,10 +49,15 @@ type Generator struct {
   // under its name for reference in GenFiles  and partials in templates
   NamedTemplates map[string]string
   NamedPartials  map[string]string
+  // Base templates with overridable blocks, files set Layout to use one
+  NamedLayouts   map[string]string
+
+  // User defined helpers, available to all templates and partials
+  Helpers map[string]*templates.UserHelper
 
   // Static files are available for pure cue generators that want to have static files
-  // These should be named by their filepath, but be the content of the file
-  StaticFiles map[string]string
+  // These should be named by their filepath, and have their content, mode, or symlink set
+  StaticFiles map[string]*File
 
   //
   // For file based generators
@@ -63,6 +75,9 @@ type Generator struct {
   PartialsDir string
 	PartialsDirConfig map[string]*templates.Config
 
+  // Base directory of layout templates to load
+  LayoutsDir string
+
   // Filepath globs for static files to load
   StaticGlobs []string
 
@@ -75,14 +90,35 @@ type Generator struct {
 	// TODO, make this field available in cuelang?
 	Disabled bool
 
+	// Set when generating had errors, so dependents are skipped
+	Failed bool
+	// Errors not specific to a file, file errors are on the file
+	Errors []error
+	// Outputs removed because the generator no longer has them
+	Deleted []string
+
+	// Output selection, unselected files are neither rendered nor cleaned up
+	Filter FileFilter
+
 	// Template System Cache
 	PartialsMap templates.TemplateMap
+	LayoutsMap  templates.TemplateMap
 	TemplateMap templates.TemplateMap
 
 	// Files and the shadow dir for doing neat things
 	Files map[string]*File
 	Shadow map[string]*File
 
+	// Files are decoded, rendered, and written this many at a time when > 0
+	BatchSize int
+	// In as a Cue value with InData, for data files
+	inValue cue.Value
+	// Out and its selected filepaths, kept until streamed
+	outValue    cue.Value
+	streamPaths map[string]bool
+	// The loaded state, for running again
+	loaded *snapshot
+
 	// Status for this generator and processing
 	Stats *GeneratorStats
 
@@ -95,6 +131,7 @@ func NewGenerator(label string, value cue.Value) *Generator{
 		Name: label,
 		CueValue: value,
 		PartialsMap: templates.NewMap(),
+		LayoutsMap: templates.NewMap(),
 		TemplateMap: templates.NewMap(),
 		Files: make(map[string]*File),
 		Shadow: make(map[string]*File),
@@ -102,12 +139,24 @@ func NewGenerator(label string, value cue.Value) *Generator{
 	}
 }
 
+// Fail marks the generator as failed, so dependents are skipped,
+// and records any errors which are not specific to a file
+func (G *Generator) Fail(errs ...error) {
+	G.Failed = true
+	G.Errors = append(G.Errors, errs...)
+}
+
 func (G *Generator) GenerateFiles() []error {
+	return G.renderFiles(G.Files)
+}
+
+// renderFiles renders a set of the generator's files, adding to the rendering time
+func (G *Generator) renderFiles(files map[string]*File) []error {
 	errs := []error{}
 
 	start := time.Now()
 
-	for _, F := range G.Files {
+	for _, F := range files {
 
 		// fmt.Printf("GenerateFile: %s\n%#+v\n\n", F.Filepath, F)
 		if F.Filepath == "" {
@@ -126,7 +175,7 @@ func (G *Generator) GenerateFiles() []error {
 	}
 
 	elapsed := time.Now().Sub(start).Round(time.Millisecond)
-	G.Stats.RenderingTime = elapsed
+	G.Stats.RenderingTime += elapsed
 
 	return errs
 }
@@ -136,6 +185,12 @@ func (G *Generator) Initialize() ([]error) {
 	var errs []error
 	// fmt.Println("Intitializing Generator: ", G.Name)
 
+	// Helpers are needed before any templates are parsed
+	errs = G.initHelpers()
+	if len(errs) > 0 {
+		return errs
+	}
+
 	// First do partials, so available to all templates
 	errs = G.initPartials()
 	if len(errs) > 0 {
@@ -144,6 +199,11 @@ func (G *Generator) Initialize() ([]error) {
 	}
 	// fmt.Println("  Partials:", G.PartialsMap )
 
+	errs = G.initLayouts()
+	if len(errs) > 0 {
+		return errs
+	}
+
 	errs = G.initTemplates()
 	if len(errs) > 0 {
 		// fmt.Printf("initTemplates Errors:\n%v\n", errs)
@@ -157,6 +217,7 @@ func (G *Generator) Initialize() ([]error) {
 		return errs
 	}
 
+
 	// fmt.Println("Intitialized Generator: ", G.Name)
 	// fmt.Printf("%# v\n", pretty.Formatter(G))
 
@@ -165,6 +226,44 @@ func (G *Generator) Initialize() ([]error) {
 
 const CUE_VENDOR_DIR = "./cue.mod/pkg/"
 
+// packageDir is where the generator's files live, relative to the working directory
+func (G *Generator) packageDir() string {
+	if G.PackageName == "" {
+		return ""
+	}
+	return path.Join(CUE_VENDOR_DIR, G.PackageName)
+}
+
+// packagePath is where a directory of the generator is, within the vendored package when it has one
+func (G *Generator) packagePath(dir string) string {
+	if G.PackageName == "" {
+		return dir
+	}
+	return path.Join(CUE_VENDOR_DIR, G.PackageName, dir)
+}
+
+func (G *Generator) initHelpers() []error {
+	if len(G.Helpers) == 0 {
+		return nil
+	}
+
+	helpers, err := templates.CreateUserHelpers(G.Helpers, G.TemplateConfig)
+	if err != nil {
+		return []error{fmt.Errorf("Generator: %q %w", G.Name, err)}
+	}
+
+	// Every config used to create templates needs the helpers
+	G.TemplateConfig.Helpers = helpers
+	for _, C := range G.PartialsDirConfig {
+		C.Helpers = helpers
+	}
+	for _, C := range G.TemplatesDirConfig {
+		C.Helpers = helpers
+	}
+
+	return nil
+}
+
 func (G *Generator) initPartials() []error {
 	var errs []error
 
@@ -180,10 +279,7 @@ func (G *Generator) initPartials() []error {
 	}
 
 	// Then file based partials, but don't overwrite
-	pDir := G.PartialsDir
-	if G.PackageName != "" {
-		pDir = path.Join(CUE_VENDOR_DIR, G.PackageName, G.PartialsDir)
-	}
+	pDir := G.packagePath(G.PartialsDir)
 	pMap, err := templates.CreateTemplateMapFromFolder(pDir, G.TemplateConfig.TemplateSystem, G.TemplateConfig, G.PartialsDirConfig)
 	if err != nil {
 		return append(errs, err)
@@ -203,6 +299,46 @@ func (G *Generator) initPartials() []error {
 	return errs
 }
 
+func (G *Generator) initLayouts() []error {
+	var errs []error
+
+	// First named
+	for k, content := range G.NamedLayouts {
+		T, err := templates.CreateFromString(k, content, G.TemplateConfig.TemplateSystem, G.TemplateConfig)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		G.LayoutsMap[k] = T
+	}
+
+	// Then file based layouts, but don't overwrite
+	if G.LayoutsDir != "" {
+		lDir := G.packagePath(G.LayoutsDir)
+		lMap, err := templates.CreateTemplateMapFromFolder(lDir, G.TemplateConfig.TemplateSystem, G.TemplateConfig, nil)
+		if err != nil {
+			return append(errs, err)
+		}
+
+		for k, T := range lMap {
+			if strings.HasPrefix(k, "/") {
+				k = k[1:]
+			}
+			_, ok := G.LayoutsMap[k]
+			if !ok {
+				G.LayoutsMap[k] = T
+			}
+		}
+	}
+
+	// Layouts can use partials too
+	for _, L := range G.LayoutsMap {
+		G.registerPartials(L)
+	}
+
+	return errs
+}
+
 func (G *Generator) initTemplates() []error {
 	var errs []error
 
@@ -217,10 +353,7 @@ func (G *Generator) initTemplates() []error {
 	}
 
 	// Then file based template, but don't overwrite
-	tDir := G.TemplatesDir
-	if G.PackageName != "" {
-		tDir = path.Join(CUE_VENDOR_DIR, G.PackageName, G.TemplatesDir)
-	}
+	tDir := G.packagePath(G.TemplatesDir)
 	tMap, err := templates.CreateTemplateMapFromFolder(tDir, G.TemplateConfig.TemplateSystem, G.TemplateConfig, G.TemplatesDirConfig)
 	if err != nil {
 		return append(errs, err)
@@ -272,6 +405,30 @@ func (G *Generator) ResolveFile(F *File) error {
 		F.TemplateConfig.OverrideDotDefaults(G.TemplateConfig)
 	}
 
+	// Binary files and symlinks have no template
+	if F.Symlink != "" || F.Base64 != "" || F.Source != "" {
+		err := F.LoadContent(G.packageDir())
+		if err != nil {
+			F.IsErr = 1
+			F.Errors = append(F.Errors, err)
+			return err
+		}
+		return nil
+	}
+
+	// Data files serialize their value, no template text needed
+	if F.DataFormat != "" || F.TemplateConfig.TemplateSystem == "data" {
+		T, err := templates.CreateFromString(F.Filepath, F.DataFormat, "data", F.TemplateConfig)
+		if err != nil {
+			F.IsErr = 1
+			F.Errors = append(F.Errors, err)
+			return err
+		}
+
+		F.TemplateInstance = T
+		return nil
+	}
+
 	// both valued?
 	if F.Template != "" && F.TemplateName != "" {
 		err := fmt.Errorf("Cannot specify both Template and TemplateName in Gen: %q File: %q TName: %q\n", G.Name, F.Filepath, F.TemplateName)
@@ -298,7 +455,7 @@ func (G *Generator) ResolveFile(F *File) error {
 		}
 
 		F.TemplateInstance = T
-		return nil
+		return G.applyLayout(F)
 	}
 
 	if F.Template != "" {
@@ -316,6 +473,30 @@ func (G *Generator) ResolveFile(F *File) error {
 
 	// fmt.Println("    TI:", F.TemplateInstance)
 
+	return G.applyLayout(F)
+}
+
+func (G *Generator) applyLayout(F *File) error {
+	if F.Layout == "" || F.TemplateInstance == nil {
+		return nil
+	}
+
+	L, ok := G.LayoutsMap[F.Layout]
+	if !ok {
+		err := fmt.Errorf("Layout %q not found for %s %s\n", F.Layout, G.Name, F.Filepath)
+		F.IsErr = 1
+		F.Errors = append(F.Errors, err)
+		return err
+	}
+
+	T, err := templates.ApplyLayout(L, F.TemplateInstance)
+	if err != nil {
+		F.IsErr = 1
+		F.Errors = append(F.Errors, err)
+		return err
+	}
+
+	F.TemplateInstance = T
 	return nil
 }
 
@@ -331,14 +512,22 @@ func (G *Generator) registerPartials(T *templates.Template) {
 	if T.T != nil {
 		for k, P := range G.PartialsMap {
 			// fmt.Println("Partial - Golang -", k)
-			if T.Config.TemplateSystem == P.Config.TemplateSystem {
-				t := T.T.New(k)
-				// TODO Delims again here?
-				templates.AddGolangHelpers(t)
-
-				t.Parse(P.Source)
-
-				// T.T = t
+			// Share the already parsed trees rather than parsing again
+			if T.Config.TemplateSystem == P.Config.TemplateSystem && P.T != nil {
+				for _, p := range P.T.Templates() {
+					if p.Tree == nil {
+						continue
+					}
+					name := p.Name()
+					if name == P.T.Name() {
+						name = k
+					}
+					// don't clobber the template's own defines
+					if name != k && T.T.Lookup(name) != nil {
+						continue
+					}
+					T.T.AddParseTree(name, p.Tree)
+				}
 			}
 		}
 	}
@@ -0,0 +1,298 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"github.com/clbanning/mxj"
+	"github.com/ghodss/yaml"
+	"github.com/naoina/toml"
+)
+
+// DataSource is a data file outside of Cue, loaded into In under its name
+type DataSource struct {
+	Name   string
+	Path   string // relative to where hof is run
+	Format string // json, yaml, csv, toml, xml, sqlite, empty infers from Path
+	Query  string // sqlite only, rows become a list of objects, needs sqlite3 >= 3.33
+
+	// Cue schema the data is validated against, defaults are filled too
+	Schema cue.Value
+}
+
+type dataDecoder func(DS *DataSource, content []byte) (interface{}, error)
+
+var dataDecoders = map[string]dataDecoder{
+	"json":   decodeJSONData,
+	"yaml":   decodeYAMLData,
+	"yml":    decodeYAMLData,
+	"csv":    decodeCSVData,
+	"toml":   decodeTOMLData,
+	"xml":    decodeXMLData,
+	"sqlite": decodeSQLiteData,
+	"db":     decodeSQLiteData,
+}
+
+// loadInData reads the InData sources, validates them, and adds them to the decoded 'In'.
+// The schemas are definitions, so they are skipped when the generator is decoded
+// and we look them up in the Cue value instead.
+func (G *Generator) loadInData(gen map[string]interface{}) []error {
+	var errs []error
+
+	sources, ok := gen["InData"].(map[string]interface{})
+	if !ok || len(sources) == 0 {
+		return nil
+	}
+
+	In, ok := gen["In"].(map[string]interface{})
+	if !ok {
+		In = make(map[string]interface{})
+		gen["In"] = In
+	}
+
+	G.InData = make(map[string]*DataSource)
+	for name, sI := range sources {
+		s, ok := sI.(map[string]interface{})
+		if !ok {
+			errs = append(errs, fmt.Errorf("Generator: %q InData %q is not an object.", G.Name, name))
+			continue
+		}
+
+		DS := &DataSource{Name: name}
+		DS.Path, _ = s["Path"].(string)
+		DS.Format, _ = s["Format"].(string)
+		DS.Query, _ = s["Query"].(string)
+		// the schema is optional
+		if S := G.CueValue.Lookup("InData", name).LookupDef("#Schema"); S.Err() == nil {
+			DS.Schema = S
+		}
+		G.InData[name] = DS
+
+		data, err := DS.Load()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Generator: %q InData %q: %w", G.Name, name, err))
+			continue
+		}
+
+		In[name] = data
+	}
+
+	return errs
+}
+
+// Load reads, decodes, and validates the data source
+func (DS *DataSource) Load() (interface{}, error) {
+	format := DS.Format
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(DS.Path), ".")
+	}
+	dec, ok := dataDecoders[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown data format %q for %q, should be one of json, yaml, csv, toml, xml, sqlite", format, DS.Path)
+	}
+
+	var content []byte
+	var err error
+	// sqlite reads the file itself
+	if format != "sqlite" && format != "db" {
+		content, err = ioutil.ReadFile(DS.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := dec(DS, content)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %q: %w", DS.Path, err)
+	}
+
+	return DS.validate(data)
+}
+
+// validate unifies the data with the schema, and returns it with any defaults filled
+func (DS *DataSource) validate(data interface{}) (interface{}, error) {
+	if !DS.Schema.Exists() {
+		return data, nil
+	}
+
+	V := DS.Schema.Fill(data)
+	err := V.Validate(cue.Concrete(true))
+	if err != nil {
+		return nil, fmt.Errorf("%q does not match the schema: %w", DS.Path, err)
+	}
+
+	var final interface{}
+	err = V.Decode(&final)
+	if err != nil {
+		return nil, err
+	}
+	return final, nil
+}
+
+// json.Number keeps ints as ints, so they pass 'int' in schemas
+func decodeJSONData(DS *DataSource, content []byte) (interface{}, error) {
+	var data interface{}
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	err := dec.Decode(&data)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeNumbers(data), nil
+}
+
+func decodeYAMLData(DS *DataSource, content []byte) (interface{}, error) {
+	j, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONData(DS, j)
+}
+
+func decodeTOMLData(DS *DataSource, content []byte) (interface{}, error) {
+	data := make(map[string]interface{})
+	err := toml.Unmarshal(content, &data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// The root element is kept, numbers and bools are cast
+func decodeXMLData(DS *DataSource, content []byte) (interface{}, error) {
+	m, err := mxj.NewMapXml(content, true)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}(m), nil
+}
+
+// The first row is the header, each row becomes an object
+func decodeCSVData(DS *DataSource, content []byte) (interface{}, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []interface{}{}
+	if len(records) == 0 {
+		return rows, nil
+	}
+
+	header := records[0]
+	for _, rec := range records[1:] {
+		row := make(map[string]interface{}, len(header))
+		for i, col := range header {
+			row[col] = csvValue(rec[i])
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// csvValue types a cell like a Cue literal would be, i.e. int, float, bool, or string
+func csvValue(cell string) interface{} {
+	if i, err := strconv.ParseInt(cell, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(cell, 64); err == nil {
+		return f
+	}
+	if cell == "true" || cell == "false" {
+		return cell == "true"
+	}
+	return cell
+}
+
+// Uses the sqlite3 command, so hof does not need cgo.
+// It has to be on the PATH, and 3.33 or later for the -json output mode.
+func decodeSQLiteData(DS *DataSource, content []byte) (interface{}, error) {
+	if DS.Query == "" {
+		return nil, fmt.Errorf("sqlite data requires a Query")
+	}
+
+	err := checkSQLite()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("sqlite3", "-json", "-readonly", DS.Path, DS.Query)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("sqlite3 %s: %v: %s", DS.Path, err, strings.TrimSpace(stderr.String()))
+	}
+
+	// no rows prints nothing
+	if len(bytes.TrimSpace(out)) == 0 {
+		return []interface{}{}, nil
+	}
+
+	return decodeJSONData(DS, out)
+}
+
+// The first version with the -json output mode
+var sqliteMinVersion = [3]int{3, 33, 0}
+
+// checkSQLite errors when the sqlite3 command is missing or too old
+func checkSQLite() error {
+	out, err := exec.Command("sqlite3", "-version").Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("sqlite data requires the sqlite3 command-line program, version 3.33 or later, and it is not on the PATH")
+	}
+	if err != nil {
+		return fmt.Errorf("sqlite data requires the sqlite3 command-line program, version 3.33 or later: %w", err)
+	}
+
+	// i.e. "3.31.1 2020-01-27 19:55:54 ..."
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return fmt.Errorf("sqlite data requires sqlite3 3.33 or later, could not read the version %q", out)
+	}
+	var version [3]int
+	for i, part := range strings.SplitN(fields[0], ".", 3) {
+		version[i], _ = strconv.Atoi(part)
+	}
+
+	for i := range version {
+		if version[i] != sqliteMinVersion[i] {
+			if version[i] < sqliteMinVersion[i] {
+				return fmt.Errorf("sqlite data requires sqlite3 3.33 or later for -json, found %s", fields[0])
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+func normalizeNumbers(data interface{}) interface{} {
+	switch D := data.(type) {
+	case map[string]interface{}:
+		for k, v := range D {
+			D[k] = normalizeNumbers(v)
+		}
+	case []interface{}:
+		for i, v := range D {
+			D[i] = normalizeNumbers(v)
+		}
+	case json.Number:
+		if i, err := D.Int64(); err == nil {
+			return i
+		}
+		f, _ := D.Float64()
+		return f
+	}
+	return data
+}
@@ -0,0 +1,148 @@
+package gen_test
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+type dataCase struct {
+	Path    string
+	Content string
+	Expect  interface{}
+}
+
+var dataCases = []dataCase{
+	{"a.json", `{"name": "a", "count": 2, "ratio": 0.5, "tags": ["x"]}`, map[string]interface{}{
+		"name": "a", "count": int64(2), "ratio": 0.5, "tags": []interface{}{"x"},
+	}},
+	{"a.yaml", "name: a\ncount: 2\nok: true\n", map[string]interface{}{
+		"name": "a", "count": int64(2), "ok": true,
+	}},
+	{"a.yml", "- 1\n- two\n", []interface{}{int64(1), "two"}},
+	{"a.toml", "name = \"a\"\n[sub]\nok = true\n", map[string]interface{}{
+		"name": "a", "sub": map[string]interface{}{"ok": true},
+	}},
+	{"a.xml", "<root><name>a</name><count>2</count><ok>true</ok></root>", map[string]interface{}{
+		"root": map[string]interface{}{"name": "a", "count": 2.0, "ok": true},
+	}},
+	// cells are typed like Cue literals
+	{"a.csv", "feature,count,ratio,enabled,note\nsso,3,1.5,true,\nlogs,0,2,false,v1.2\n", []interface{}{
+		map[string]interface{}{"feature": "sso", "count": int64(3), "ratio": 1.5, "enabled": true, "note": ""},
+		map[string]interface{}{"feature": "logs", "count": int64(0), "ratio": int64(2), "enabled": false, "note": "v1.2"},
+	}},
+	{"empty.csv", "", []interface{}{}},
+}
+
+func TestDataSourceFormats(t *testing.T) {
+	defer inTempDir(t)()
+
+	for _, c := range dataCases {
+		writeFiles(t, map[string]string{c.Path: c.Content})
+
+		DS := &gen.DataSource{Name: "data", Path: c.Path}
+		data, err := DS.Load()
+		assert.NoError(t, err, c.Path)
+		assert.Equal(t, c.Expect, data, c.Path)
+	}
+
+	// the format overrides the extension
+	writeFiles(t, map[string]string{"data.txt": `{"a": 1}`})
+	DS := &gen.DataSource{Path: "data.txt", Format: "json"}
+	data, err := DS.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": int64(1)}, data)
+
+	DS = &gen.DataSource{Path: "data.txt"}
+	_, err = DS.Load()
+	assert.Error(t, err)
+
+	DS = &gen.DataSource{Path: "missing.json"}
+	_, err = DS.Load()
+	assert.Error(t, err)
+}
+
+func TestDataSourceSchema(t *testing.T) {
+	defer inTempDir(t)()
+
+	var r cue.Runtime
+	I, err := r.Compile("schema.cue", `
+#Schema: [...{
+	feature: string
+	count:   int & >=0
+	tier:    *"free" | "pro"
+}]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := I.LookupDef("#Schema")
+
+	// defaults are filled in, numbers decode from Cue like the rest of In
+	writeFiles(t, map[string]string{"ok.csv": "feature,count\nsso,3\n"})
+	DS := &gen.DataSource{Path: "ok.csv", Schema: schema}
+	data, err := DS.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"feature": "sso", "count": 3.0, "tier": "free"},
+	}, data)
+
+	for fn, content := range map[string]string{
+		"negative.csv": "feature,count\nsso,-1\n",
+		"string.csv":   "feature,count\nsso,many\n",
+		"missing.csv":  "feature\nsso\n",
+	} {
+		writeFiles(t, map[string]string{fn: content})
+		DS := &gen.DataSource{Path: fn, Schema: schema}
+		_, err := DS.Load()
+		assert.Error(t, err, fn)
+	}
+}
+
+func TestDataSourceSQLite(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 is not installed")
+	}
+	defer inTempDir(t)()
+
+	DS := &gen.DataSource{Path: "a.db"}
+	_, err := DS.Load()
+	assert.EqualError(t, err, `decoding "a.db": sqlite data requires a Query`)
+
+	err = exec.Command("sqlite3", "a.db", "create table f (name text, count int); insert into f values ('sso', 3);").Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	DS = &gen.DataSource{Path: "a.db", Query: "select * from f"}
+	data, err := DS.Load()
+	if err != nil && strings.Contains(err.Error(), "3.33 or later") {
+		t.Skip(err)
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "sso", "count": int64(3)},
+	}, data)
+
+	DS = &gen.DataSource{Path: "a.db", Query: "select * from f where count > 5"}
+	data, err = DS.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{}, data)
+}
+
+func TestDataSourceSQLiteMissing(t *testing.T) {
+	defer inTempDir(t)()
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", "")
+
+	DS := &gen.DataSource{Path: "a.db", Query: "select * from f"}
+	_, err := DS.Load()
+	assert.EqualError(t, err, `decoding "a.db": sqlite data requires the sqlite3 command-line program, version 3.33 or later, and it is not on the PATH`)
+}
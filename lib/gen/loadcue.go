@@ -2,6 +2,7 @@ package gen
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"cuelang.org/go/cue"
@@ -13,11 +14,17 @@ func (G *Generator) LoadCue() ([]error) {
 	// fmt.Println("Gen Load:", G.Name)
 
 	var gen map[string]interface{}
+	var err error
 	start := time.Now()
 
-	// Decode the value into a temporary "generator" with timing
-	err := G.CueValue.Decode(&gen)
-		if err != nil {
+	// Decode the value into a temporary "generator" with timing,
+	// when streaming, Out is decoded later a batch at a time
+	if G.IsStreaming() {
+		gen, err = G.decodeStreaming()
+	} else {
+		err = G.CueValue.Decode(&gen)
+	}
+	if err != nil {
 		return []error{err}
 	}
 
@@ -25,6 +32,12 @@ func (G *Generator) LoadCue() ([]error) {
 	cueDecodeTime := time.Now()
 	G.Stats.CueLoadingTime = cueDecodeTime.Sub(start)
 
+	// External data joins 'In' before it is given to the files
+	errs := G.loadInData(gen)
+	if len(errs) > 0 {
+		return errs
+	}
+
 	return G.decodeGenerator(gen)
 }
 
@@ -43,6 +56,14 @@ func (G *Generator) decodeGenerator(gen map[string]interface{}) ([]error) {
 		G.In = In
 	}
 
+	// data files serialize Cue values, InData joins as decoded
+	G.inValue = G.CueValue.Lookup("In")
+	for name, _ := range G.InData {
+		if G.inValue.Exists() {
+			G.inValue = G.inValue.Fill(G.In[name], name)
+		}
+	}
+
 	G.Outdir = gen["Outdir"].(string)
 
 	//
@@ -73,10 +94,24 @@ func (G *Generator) decodeGenerator(gen map[string]interface{}) ([]error) {
 		G.TemplateConfig.RHS2_T = config["RHS2_T"].(string)
 		G.TemplateConfig.LHS3_T = config["LHS3_T"].(string)
 		G.TemplateConfig.RHS3_T = config["RHS3_T"].(string)
+
+		// the generator's templates need a template system
+		if G.TemplateConfig.TemplateSystem == "data" {
+			return []error{fmt.Errorf("Generator: %q TemplateSystem 'data' can only be set on a file's TemplateConfig, or use the file's DataFormat", G.Name)}
+		}
+		if err := checkTemplateSystem(G.TemplateConfig.TemplateSystem); err != nil {
+			return []error{fmt.Errorf("Generator: %q %w", G.Name, err)}
+		}
 	}
 
 	G.PackageName, _  = gen["PackageName"].(string)
 
+	// Generators which must run first, optional
+	deps, _ := gen["DependsOn"].([]interface{})
+	for _, d := range deps {
+		G.DependsOn = append(G.DependsOn, d.(string))
+	}
+
 	// In cue code
 	G.NamedTemplates = make(map[string]string)
 	nt, ok := gen["NamedTemplates"].(map[string]interface{})
@@ -96,13 +131,70 @@ func (G *Generator) decodeGenerator(gen map[string]interface{}) ([]error) {
 		G.NamedPartials[k] = p.(string)
 	}
 
-	G.StaticFiles = make(map[string]string)
+	// Layouts, optional
+	G.NamedLayouts = make(map[string]string)
+	nl, ok := gen["NamedLayouts"].(map[string]interface{})
+	if ok {
+		for k, l := range nl {
+			G.NamedLayouts[k] = l.(string)
+		}
+	}
+	G.LayoutsDir, _ = gen["LayoutsDir"].(string)
+
+	// User defined helpers, optional
+	G.Helpers = make(map[string]*templates.UserHelper)
+	hp, ok := gen["Helpers"].(map[string]interface{})
+	if ok {
+		for k, hI := range hp {
+			h, hok := hI.(map[string]interface{})
+			if !hok {
+				return []error{fmt.Errorf("Generator: %q helper %q is not an object.", G.Name, k)}
+			}
+			H := &templates.UserHelper{}
+			H.Template, _ = h["Template"].(string)
+			H.Expr, _ = h["Expr"].(string)
+			args, _ := h["Args"].([]interface{})
+			for _, a := range args {
+				s, sok := a.(string)
+				if !sok {
+					return []error{fmt.Errorf("Generator: %q helper %q Args must be strings, found %v", G.Name, k, a)}
+				}
+				H.Args = append(H.Args, s)
+			}
+			imports, _ := h["Imports"].([]interface{})
+			for _, i := range imports {
+				s, sok := i.(string)
+				if !sok {
+					return []error{fmt.Errorf("Generator: %q helper %q Imports must be strings, found %v", G.Name, k, i)}
+				}
+				H.Imports = append(H.Imports, s)
+			}
+			G.Helpers[k] = H
+		}
+	}
+
+	G.StaticFiles = make(map[string]*File)
 	sf, ok := gen["StaticFiles"].(map[string]interface{})
 	if !ok {
 		return []error{fmt.Errorf("Generator: %q field 'StaticFiles' is not an object.", G.Name)}
 	}
 	for k, s := range sf {
-		G.StaticFiles[k] = s.(string)
+		F := &File{ Filepath: k }
+		switch S := s.(type) {
+		case string:
+			F.RenderContent = []byte(S)
+		case map[string]interface{}:
+			content, _ := S["Content"].(string)
+			F.RenderContent = []byte(content)
+			decodeFileContent(F, S)
+			err := F.LoadContent(G.packageDir())
+			if err != nil {
+				return []error{fmt.Errorf("Generator: %q static file %q: %w", G.Name, k, err)}
+			}
+		default:
+			return []error{fmt.Errorf("Generator: %q static file %q is not a string or object.", G.Name, k)}
+		}
+		G.StaticFiles[k] = F
 	}
 
 	// Eventually loaded from disk
@@ -146,6 +238,9 @@ func (G *Generator) decodeGenerator(gen map[string]interface{}) ([]error) {
 				c.LHS3_T = config["LHS3_T"].(string)
 				c.RHS3_T = config["RHS3_T"].(string)
 
+				if err := checkTemplateSystem(c.TemplateSystem); err != nil {
+					return []error{fmt.Errorf("Generator: %q PartialsDirConfig %q %w", G.Name, fn, err)}
+				}
 				G.PartialsDirConfig[fn] = c
 			}
 		}
@@ -183,6 +278,9 @@ func (G *Generator) decodeGenerator(gen map[string]interface{}) ([]error) {
 				c.LHS3_T = config["LHS3_T"].(string)
 				c.RHS3_T = config["RHS3_T"].(string)
 
+				if err := checkTemplateSystem(c.TemplateSystem); err != nil {
+					return []error{fmt.Errorf("Generator: %q TemplatesDirConfig %q %w", G.Name, fn, err)}
+				}
 				G.TemplatesDirConfig[fn] = c
 			}
 		}
@@ -193,14 +291,25 @@ func (G *Generator) decodeGenerator(gen map[string]interface{}) ([]error) {
 
 	// Decode generator files
 	// Turn G.Out elements into G.Files
+	outs, _ := G.CueValue.Lookup("Out").List()
 	for i, O := range Out {
 		file := O.(map[string]interface{})
 
-		F, err := G.decodeFile(i, file)
+		var V cue.Value
+		if outs.Next() {
+			V = outs.Value()
+		}
+
+		F, err := G.decodeFile(i, file, V)
 		if err != nil {
 			errs = append(errs, err)
 		}
 
+		if _, ok := G.Files[F.Filepath]; ok && F.Filepath != "" {
+			errs = append(errs, fmt.Errorf("Generator: %q has more than one output for %q", G.Name, F.Filepath))
+			continue
+		}
+
 		G.Files[F.Filepath] = F
 
 	}
@@ -212,7 +321,8 @@ func (G *Generator) decodeGenerator(gen map[string]interface{}) ([]error) {
 	return errs
 }
 
-func (G *Generator) decodeFile(i int, file map[string]interface{}) (*File, error) {
+// V is the output's Cue value, for data files
+func (G *Generator) decodeFile(i int, file map[string]interface{}, V cue.Value) (*File, error) {
 
 	// Is this output missing a filename? then skip it
 	if _, ok := file["Filepath"]; !ok {
@@ -253,6 +363,12 @@ func (G *Generator) decodeFile(i int, file map[string]interface{}) (*File, error
 	F.Filepath = file["Filepath"].(string)
 	F.Template = file["Template"].(string)
 	F.TemplateName = file["TemplateName"].(string)
+	F.Layout, _ = file["Layout"].(string)
+	decodeFileContent(F, file)
+	if df, ok := file["DataFormat"]; ok {
+		F.DataFormat = df.(string)
+	}
+	F.Value = file["Value"]
 
 		// deleimters
 	configI, ok := file["TemplateConfig"]
@@ -278,8 +394,56 @@ func (G *Generator) decodeFile(i int, file map[string]interface{}) (*File, error
 		F.TemplateConfig.RHS2_T = config["RHS2_T"].(string)
 		F.TemplateConfig.LHS3_T = config["LHS3_T"].(string)
 		F.TemplateConfig.RHS3_T = config["RHS3_T"].(string)
+
+		if err := checkTemplateSystem(F.TemplateConfig.TemplateSystem); err != nil {
+			return nil, fmt.Errorf("Generator: %q file %q %w", G.Name, F.Filepath, err)
+		}
+	}
+
+	if F.DataFormat != "" || (F.TemplateConfig != nil && F.TemplateConfig.TemplateSystem == "data") {
+		F.DataValue = G.dataValue(V)
 	}
 
 	return F, nil
 }
 
+// dataValue is the Cue value a data file serializes, Value or In,
+// so the output keeps Cue's field order. As for templates, the
+// generator's In fills the fields the file's In does not have.
+// checkTemplateSystem errors for systems which are not registered,
+// "." inherits the generator's and an empty system is inferred
+func checkTemplateSystem(system string) error {
+	if system == "" || system == "." {
+		return nil
+	}
+	if _, ok := templates.LookupTemplateSystem(system); !ok {
+		return fmt.Errorf("unknown TemplateSystem %q, registered systems are %s", system, strings.Join(templates.KnownTemplateSystems(), ", "))
+	}
+	return nil
+}
+
+func (G *Generator) dataValue(V cue.Value) cue.Value {
+	if v := V.Lookup("Value"); v.Exists() {
+		return v
+	}
+
+	v := V.Lookup("In")
+	if !v.Exists() {
+		return G.inValue
+	}
+	if !G.inValue.Exists() {
+		return v
+	}
+
+	iter, err := G.inValue.Fields()
+	if err != nil {
+		return v
+	}
+	for iter.Next() {
+		if !v.Lookup(iter.Label()).Exists() {
+			v = v.Fill(iter.Value(), iter.Label())
+		}
+	}
+	return v
+}
+
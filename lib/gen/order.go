@@ -0,0 +1,58 @@
+package gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OrderGenerators sorts generators so that each comes after those it
+// depends on, otherwise by name, giving a stable order between runs.
+func OrderGenerators(gens map[string]*Generator) ([]*Generator, error) {
+	names := make([]string, 0, len(gens))
+	for name, _ := range gens {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ordered := make([]*Generator, 0, len(gens))
+	// 0 = unvisited, 1 = visiting, 2 = done
+	state := map[string]int{}
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case 2:
+			return nil
+		case 1:
+			return fmt.Errorf("Generator dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+		state[name] = 1
+
+		G := gens[name]
+		deps := append([]string{}, G.DependsOn...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, ok := gens[dep]; !ok {
+				return fmt.Errorf("Generator %q depends on unknown generator %q", name, dep)
+			}
+			err := visit(dep, append(path, name))
+			if err != nil {
+				return err
+			}
+		}
+
+		state[name] = 2
+		ordered = append(ordered, G)
+		return nil
+	}
+
+	for _, name := range names {
+		err := visit(name, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,74 @@
+package gen_test
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+func TestOrderGenerators(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Deps  map[string][]string
+		Order []string
+		Err   string
+	}{
+		{
+			Name:  "by name",
+			Deps:  map[string][]string{"c": nil, "a": nil, "b": nil},
+			Order: []string{"a", "b", "c"},
+		},
+		{
+			Name:  "dependencies first",
+			Deps:  map[string][]string{"a": {"c"}, "b": nil, "c": {"b"}},
+			Order: []string{"b", "c", "a"},
+		},
+		{
+			Name:  "shared dependency once",
+			Deps:  map[string][]string{"a": {"c", "b"}, "b": {"c"}, "c": nil},
+			Order: []string{"c", "b", "a"},
+		},
+		{
+			Name: "cycle",
+			Deps: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}},
+			Err:  "Generator dependency cycle: a -> b -> c -> a",
+		},
+		{
+			Name: "self",
+			Deps: map[string][]string{"a": {"a"}},
+			Err:  "Generator dependency cycle: a -> a",
+		},
+		{
+			Name: "unknown",
+			Deps: map[string][]string{"a": {"missing"}},
+			Err:  `Generator "a" depends on unknown generator "missing"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			gens := map[string]*gen.Generator{}
+			for name, deps := range c.Deps {
+				G := gen.NewGenerator(name, cue.Value{})
+				G.DependsOn = deps
+				gens[name] = G
+			}
+
+			ordered, err := gen.OrderGenerators(gens)
+			if c.Err != "" {
+				assert.EqualError(t, err, c.Err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var names []string
+			for _, G := range ordered {
+				names = append(names, G.Name)
+			}
+			assert.Equal(t, c.Order, names)
+		})
+	}
+}
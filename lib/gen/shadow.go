@@ -1,11 +1,12 @@
 package gen
 
 import (
+	"bytes"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -67,7 +68,7 @@ func (F *File) ReadShadow() error {
 	// Should have already been confirmed to exist at this point
 	shadowFN := path.Join(SHADOW_DIR, F.ShadowFile.Filepath)
 	// fmt.Println("ReadShadow", shadowFN)
-	bytes, err := ioutil.ReadFile(shadowFN)
+	bytes, err := readFileOrLink(shadowFN)
 	if err != nil {
 		return err
 	}
@@ -77,3 +78,56 @@ func (F *File) ReadShadow() error {
 	return nil
 }
 
+
+// RemoveOrphans deletes the outputs the generator no longer has, along with their shadow.
+// Outputs the user modified since they were shadowed are left in place, as are any
+// files the generator still has, i.e. those which had errors. The shadow should only
+// hold the orphans at this point, files are removed from it as they are written.
+func (G *Generator) RemoveOrphans() (deleted []string, errs []error) {
+	keys := make([]string, 0, len(G.Shadow))
+	for key, _ := range G.Shadow {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fp := strings.TrimPrefix(key, G.Name+"/")
+		if _, ok := G.Files[fp]; ok {
+			continue
+		}
+
+		shadowFN := path.Join(SHADOW_DIR, key)
+		shadow, err := readFileOrLink(shadowFN)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		user, err := readFileOrLink(fp)
+		if err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err == nil {
+			if !bytes.Equal(user, shadow) {
+				continue
+			}
+			err = os.Remove(fp)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			deleted = append(deleted, fp)
+		}
+
+		err = os.Remove(shadowFN)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delete(G.Shadow, key)
+	}
+
+	return deleted, errs
+}
@@ -0,0 +1,56 @@
+package gen_test
+
+import (
+	"os"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+func TestRemoveOrphans(t *testing.T) {
+	defer inTempDir(t)()
+
+	writeFiles(t, map[string]string{
+		// unmodified orphan, deleted
+		"out/old.txt":               "old",
+		".hof/shadow/G/out/old.txt": "old",
+		// the user modified it, kept
+		"out/edited.txt":               "edited by the user",
+		".hof/shadow/G/out/edited.txt": "edited",
+		// the user already deleted it, only the shadow goes
+		".hof/shadow/G/out/gone.txt": "gone",
+		// still generated, but had an error this run, kept
+		"out/failed.txt":               "failed",
+		".hof/shadow/G/out/failed.txt": "failed",
+	})
+
+	G := gen.NewGenerator("G", cue.Value{})
+	failed := &gen.File{Filepath: "out/failed.txt"}
+	failed.IsErr = 1
+	G.Files["out/failed.txt"] = failed
+
+	shadow, err := gen.LoadShadow(G.Name, false)
+	assert.NoError(t, err)
+	G.Shadow = shadow
+
+	deleted, errs := G.RemoveOrphans()
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"out/old.txt"}, deleted)
+
+	exists := func(fn string) bool {
+		_, err := os.Lstat(fn)
+		return err == nil
+	}
+
+	assert.False(t, exists("out/old.txt"))
+	assert.False(t, exists(".hof/shadow/G/out/old.txt"))
+	assert.False(t, exists(".hof/shadow/G/out/gone.txt"))
+
+	assert.True(t, exists("out/edited.txt"))
+	assert.True(t, exists(".hof/shadow/G/out/edited.txt"))
+	assert.True(t, exists("out/failed.txt"))
+	assert.True(t, exists(".hof/shadow/G/out/failed.txt"))
+}
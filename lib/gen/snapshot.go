@@ -0,0 +1,131 @@
+package gen
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"cuelang.org/go/cue"
+)
+
+// Long running processes, like 'hof gen serve', keep generators loaded between runs.
+// A snapshot of the loaded files lets a generator run again without decoding
+// the Cue or parsing templates, and files are looked up for rendering on demand.
+
+type snapshot struct {
+	files       map[string]File
+	streamPaths map[string]bool
+	outValue    cue.Value
+	disabled    bool
+}
+
+// Snapshot records the loaded state of the generator, call once it is loaded
+func (G *Generator) Snapshot() {
+	S := &snapshot{
+		files:    make(map[string]File, len(G.Files)),
+		outValue: G.outValue,
+		disabled: G.Disabled,
+	}
+	for fp, F := range G.Files {
+		S.files[fp] = *F
+	}
+	if G.streamPaths != nil {
+		S.streamPaths = make(map[string]bool, len(G.streamPaths))
+		for fp, _ := range G.streamPaths {
+			S.streamPaths[fp] = true
+		}
+	}
+	G.loaded = S
+}
+
+// Reset returns the generator to the snapshot, templates stay parsed
+func (G *Generator) Reset() {
+	S := G.loaded
+	if S == nil {
+		return
+	}
+
+	G.Files = make(map[string]*File, len(S.files))
+	for fp, F := range S.files {
+		f := F
+		f.Errors = append([]error(nil), F.Errors...)
+		G.Files[fp] = &f
+	}
+	if S.streamPaths != nil {
+		G.streamPaths = make(map[string]bool, len(S.streamPaths))
+		for fp, _ := range S.streamPaths {
+			G.streamPaths[fp] = true
+		}
+		G.outValue = S.outValue
+	}
+
+	G.Shadow = make(map[string]*File)
+	G.Stats = &GeneratorStats{}
+	G.Disabled = S.disabled
+	G.Failed = false
+	G.Errors = nil
+	G.Deleted = nil
+}
+
+// LookupFile finds an output by filepath, streamed outputs are decoded and resolved
+func (G *Generator) LookupFile(fp string) (*File, error) {
+	if F, ok := G.Files[fp]; ok {
+		return F, nil
+	}
+	if !G.streamPaths[fp] {
+		return nil, nil
+	}
+
+	list, err := G.outValue.List()
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; list.Next(); i++ {
+		lfp, err := list.Value().Lookup("Filepath").String()
+		if err != nil || lfp != fp {
+			continue
+		}
+
+		var file map[string]interface{}
+		err = list.Value().Decode(&file)
+		if err != nil {
+			return nil, err
+		}
+		F, err := G.decodeFile(i, file, list.Value())
+		if err != nil {
+			return nil, err
+		}
+		err = G.ResolveFile(F)
+		if err != nil {
+			return nil, err
+		}
+		return F, nil
+	}
+
+	return nil, fmt.Errorf("Generator: %q output %q is no longer in Out", G.Name, fp)
+}
+
+// SourcePaths are the files and directories read from disk when the generator
+// is loaded, the template, partial, and layout directories, InData, and Source files
+func (G *Generator) SourcePaths() []string {
+	var paths []string
+
+	for _, dir := range []string{G.TemplatesDir, G.PartialsDir, G.LayoutsDir} {
+		if dir != "" {
+			paths = append(paths, G.packagePath(dir))
+		}
+	}
+	for _, DS := range G.InData {
+		if DS.Path != "" {
+			paths = append(paths, DS.Path)
+		}
+	}
+	for _, F := range G.Files {
+		if F.Source != "" {
+			paths = append(paths, path.Join(G.packageDir(), F.Source))
+		}
+	}
+
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,145 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-zglob"
+)
+
+// InitStaticFiles adds static files to the generator files so they are
+// shadowed and merged like rendered files. Order is important here for
+// implicit overriding, generated files win over StaticFiles, which win over StaticGlobs.
+// This reads from disk, so it is run after any generators this one depends on.
+func (G *Generator) InitStaticFiles() []error {
+	var errs []error
+
+	for p, S := range G.StaticFiles {
+		F := &File{
+			Filepath:      path.Join(G.Outdir, p),
+			Mode:          S.Mode,
+			Symlink:       S.Symlink,
+			IsBinary:      S.IsBinary,
+			RenderContent: S.RenderContent,
+			Gen:           G,
+		}
+		F.IsStatic = 1
+		G.addStaticFile(F)
+	}
+
+	matches, errs := G.staticGlobMatches()
+	for _, M := range matches {
+		content, err := ioutil.ReadFile(M.Source)
+		if err != nil {
+			err = fmt.Errorf("while reading static file %q\n%w\n", M.Source, err)
+			errs = append(errs, err)
+			continue
+		}
+
+		// the source's permissions are the declared mode
+		F := &File{
+			Filepath:      M.Filepath,
+			Mode:          M.Mode,
+			IsBinary:      isBinary(content),
+			RenderContent: content,
+			Gen:           G,
+		}
+		F.IsStatic = 1
+		G.addStaticFile(F)
+	}
+
+	return errs
+}
+
+// StaticGlobPaths are the output paths of the files the StaticGlobs match right now,
+// dependencies may add more before InitStaticFiles is run
+func (G *Generator) StaticGlobPaths() ([]string, []error) {
+	matches, errs := G.staticGlobMatches()
+	fps := make([]string, 0, len(matches))
+	for _, M := range matches {
+		fps = append(fps, M.Filepath)
+	}
+	return fps, errs
+}
+
+// staticMatch is a file found by the StaticGlobs
+type staticMatch struct {
+	Source   string
+	Filepath string
+	Mode     os.FileMode
+}
+
+func (G *Generator) staticGlobMatches() ([]staticMatch, []error) {
+	var errs []error
+	var found []staticMatch
+
+	bdir := G.packageDir()
+	for _, Glob := range G.StaticGlobs {
+		matches, err := zglob.Glob(path.Join(bdir, Glob))
+		if err != nil {
+			err = fmt.Errorf("while globbing %s / %s\n%w\n", bdir, Glob, err)
+			errs = append(errs, err)
+			continue
+		}
+
+		prefix := staticGlobPrefix(Glob)
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if info.IsDir() {
+				continue
+			}
+
+			// relative to the package, then without the glob's leading directory
+			rel := strings.TrimPrefix(match, path.Clean(bdir)+"/")
+			rel = strings.TrimPrefix(rel, prefix)
+
+			found = append(found, staticMatch{
+				Source:   match,
+				Filepath: path.Join(G.Outdir, rel),
+				Mode:     info.Mode().Perm(),
+			})
+		}
+	}
+
+	return found, errs
+}
+
+func (G *Generator) addStaticFile(F *File) {
+	if _, ok := G.Files[F.Filepath]; ok || G.streamPaths[F.Filepath] {
+		return
+	}
+	G.Files[F.Filepath] = F
+}
+
+// staticGlobPrefix is the first directory of a glob, which is trimmed from
+// the output path, (i.e. "static/**/*" -> "static/"). Globs without a leading
+// directory, or where it has wildcards, have nothing trimmed.
+func staticGlobPrefix(glob string) string {
+	glob = path.Clean(glob)
+	pos := strings.Index(glob, "/")
+	if pos < 0 {
+		return ""
+	}
+
+	first := glob[:pos]
+	if strings.ContainsAny(first, "*?[{\\") {
+		return ""
+	}
+
+	return first + "/"
+}
+
+// A NUL byte or invalid UTF-8 is a good enough sign the content is not text
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content)
+}
@@ -0,0 +1,103 @@
+package gen_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+// inTempDir runs the test from a temporary directory, outputs and the shadow are relative
+func inTempDir(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "hof-gen-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		os.Chdir(wd)
+		os.RemoveAll(dir)
+	}
+}
+
+func writeFiles(t *testing.T, files map[string]string) {
+	for fn, content := range files {
+		if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fn, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStaticFiles(t *testing.T) {
+	defer inTempDir(t)()
+
+	writeFiles(t, map[string]string{
+		"static/a.txt":            "line 1\nline 2\nline 3",
+		"static/sub/b.bin":        "\x00\x01",
+		"static/gen.txt":          "from the glob",
+		"static/inline.txt":       "from the glob",
+		"out/a.txt":               "line 1\nline 2\nline 3 user",
+		".hof/shadow/G/out/a.txt": "line 1\nline 2\nline 3",
+	})
+	// the source changes, so the user's edit is merged with it
+	writeFiles(t, map[string]string{"static/a.txt": "line 1 new\nline 2\nline 3"})
+
+	G := gen.NewGenerator("G", cue.Value{})
+	G.Outdir = "out"
+	G.StaticGlobs = []string{"static/**/*"}
+	G.StaticFiles = map[string]*gen.File{
+		"inline.txt": &gen.File{RenderContent: []byte("from StaticFiles")},
+	}
+	G.Files["out/gen.txt"] = &gen.File{Filepath: "out/gen.txt"}
+	G.Shadow["G/out/a.txt"] = &gen.File{Filepath: "G/out/a.txt"}
+
+	// the paths are known before anything is read, to check for collisions
+	fps, errs := G.StaticGlobPaths()
+	assert.Empty(t, errs)
+	sort.Strings(fps)
+	assert.Equal(t, []string{"out/a.txt", "out/gen.txt", "out/inline.txt", "out/sub/b.bin"}, fps)
+
+	errs = G.InitStaticFiles()
+	assert.Empty(t, errs)
+
+	var paths []string
+	for p, _ := range G.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	assert.Equal(t, []string{"out/a.txt", "out/gen.txt", "out/inline.txt", "out/sub/b.bin"}, paths)
+
+	// generated files win over StaticFiles, which win over StaticGlobs
+	assert.Equal(t, 0, G.Files["out/gen.txt"].IsStatic)
+	assert.Equal(t, "from StaticFiles", string(G.Files["out/inline.txt"].RenderContent))
+	assert.True(t, G.Files["out/sub/b.bin"].IsBinary)
+	assert.False(t, G.Files["out/a.txt"].IsBinary)
+
+	delete(G.Files, "out/gen.txt")
+	errs = G.GenerateFiles()
+	assert.Empty(t, errs)
+
+	A := G.Files["out/a.txt"]
+	assert.True(t, A.DoWrite)
+	assert.Equal(t, 1, A.IsModifiedDiff3)
+	assert.Equal(t, "line 1 new\nline 2\nline 3 user", string(A.FinalContent))
+
+	B := G.Files["out/sub/b.bin"]
+	assert.True(t, B.DoWrite)
+	assert.Equal(t, 1, B.IsNew)
+}
@@ -36,6 +36,7 @@ type FileStats struct {
 	IsSkipped  int
 	IsWritten  int
 	IsErr      int
+	IsStatic   int
 
 	IsModified       int
 	IsModifiedRender int
@@ -55,15 +56,18 @@ func (S *GeneratorStats) CalcTotals(G *Generator) error {
 	sum = sum.Add(S.RenderingTime)
 
 	S.TotalTime = sum.Sub(time.Time{})
-	S.TotalFiles = len(G.Files) + S.NumStatic
+	S.TotalFiles = len(G.Files)
 
-	// Sum across files
+	// Sum across files, from zero so this can be called more than once
+	S.NumNew, S.NumSame, S.NumSkipped, S.NumWritten, S.NumErr, S.NumStatic = 0, 0, 0, 0, 0, 0
+	S.NumModified, S.NumModifiedRender, S.NumModifiedOutput, S.NumModifiedDiff3, S.NumConflicted = 0, 0, 0, 0, 0
 	for _, file := range G.Files {
 		S.NumNew += file.IsNew
 		S.NumSame += file.IsSame
 		S.NumSkipped += file.IsSkipped
 		S.NumWritten += file.IsWritten
 		S.NumErr += file.IsErr
+		S.NumStatic += file.IsStatic
 
 		S.NumModified += file.IsModified
 		S.NumModifiedRender += file.IsModifiedRender
@@ -0,0 +1,180 @@
+package gen
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"cuelang.org/go/cue"
+)
+
+// Streaming keeps memory bounded for generators with very many outputs.
+// Out is left undecoded when the generator is loaded, only the filepaths are read.
+// Then files are decoded, rendered, merged, and written a batch at a time,
+// and only their stats and errors are kept afterwards.
+
+func (G *Generator) IsStreaming() bool {
+	return G.BatchSize > 0
+}
+
+// decodeStreaming decodes all of the generator except Out
+func (G *Generator) decodeStreaming() (map[string]interface{}, error) {
+	gen := make(map[string]interface{})
+
+	iter, err := G.CueValue.Fields()
+	if err != nil {
+		return nil, err
+	}
+	for iter.Next() {
+		label := iter.Label()
+		if label == "Out" {
+			continue
+		}
+		var val interface{}
+		err := iter.Value().Decode(&val)
+		if err != nil {
+			return nil, err
+		}
+		gen[label] = val
+	}
+
+	// no files yet, they come from the stream
+	gen["Out"] = []interface{}{}
+
+	G.outValue = G.CueValue.Lookup("Out")
+	G.streamPaths = make(map[string]bool)
+
+	list, err := G.outValue.List()
+	if err != nil {
+		return nil, fmt.Errorf("Generator: %q field 'Out' is not a list: %w", G.Name, err)
+	}
+	for list.Next() {
+		// outputs without a filepath are skipped
+		fp, err := list.Value().Lookup("Filepath").String()
+		if err != nil {
+			continue
+		}
+		if G.streamPaths[fp] {
+			return nil, fmt.Errorf("Generator: %q has more than one output for %q", G.Name, fp)
+		}
+		G.streamPaths[fp] = true
+	}
+
+	return gen, nil
+}
+
+// OutputPaths are the filepaths this generator writes, including those still to be streamed
+func (G *Generator) OutputPaths() []string {
+	fps := make([]string, 0, len(G.Files)+len(G.streamPaths))
+	for fp, _ := range G.Files {
+		if fp != "" && !G.streamPaths[fp] {
+			fps = append(fps, fp)
+		}
+	}
+	for fp, _ := range G.streamPaths {
+		fps = append(fps, fp)
+	}
+	sort.Strings(fps)
+	return fps
+}
+
+// StreamFiles renders the files a batch at a time, and calls write for each batch.
+// The files already loaded, i.e. static files, go first.
+// Files are kept in G.Files after they are released, for stats and reporting.
+func (G *Generator) StreamFiles(write func(files map[string]*File) []error) (errsG []error, errsW []error) {
+	batch := make(map[string]*File)
+	flush := func() {
+		errsG = append(errsG, G.renderFiles(batch)...)
+		errsW = append(errsW, write(batch)...)
+		for _, F := range batch {
+			F.Release()
+		}
+		batch = make(map[string]*File)
+	}
+	add := func(F *File) {
+		batch[F.Filepath] = F
+		if len(batch) >= G.BatchSize {
+			flush()
+		}
+	}
+
+	for _, F := range G.Files {
+		if F.Filepath != "" {
+			add(F)
+		}
+	}
+
+	list, err := G.outValue.List()
+	if err != nil {
+		return append(errsG, err), errsW
+	}
+	for i := 0; list.Next(); i++ {
+		var file map[string]interface{}
+		err := list.Value().Decode(&file)
+		if err != nil {
+			errsG = append(errsG, err)
+			continue
+		}
+
+		F, err := G.decodeFile(i, file, list.Value())
+		if err != nil {
+			errsG = append(errsG, err)
+			continue
+		}
+		if F.Filepath == "" {
+			G.Files[F.Filepath] = F
+			continue
+		}
+		// unselected or ejected
+		if !G.streamPaths[F.Filepath] {
+			continue
+		}
+		G.Files[F.Filepath] = F
+
+		err = G.ResolveFile(F)
+		if err != nil {
+			errsG = append(errsG, err)
+			// not an orphan, keep the user's file
+			delete(G.Shadow, path.Join(G.Name, F.Filepath))
+			continue
+		}
+
+		add(F)
+	}
+
+	if len(batch) > 0 {
+		flush()
+	}
+
+	// all decoded, let Cue go
+	G.outValue = cue.Value{}
+
+	return errsG, errsW
+}
+
+// Release drops the inputs and content of a written file, the stats remain
+func (F *File) Release() {
+	F.In = nil
+	F.Value = nil
+	F.DataValue = cue.Value{}
+	F.Template = ""
+	F.TemplateInstance = nil
+	F.RenderContent = nil
+	F.FinalContent = nil
+	F.ShadowFile = nil
+	F.UserFile = nil
+}
+
+// filterStreamPaths applies the output selection to files not yet streamed
+func (G *Generator) filterStreamPaths(keep func(fp string) (bool, error)) error {
+	for fp, _ := range G.streamPaths {
+		ok, err := keep(fp)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			delete(G.streamPaths, fp)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package gen
 
 import (
 	"io/ioutil"
+	"os"
 	"path"
 
 	"github.com/hofstadter-io/hof/lib/yagu"
@@ -18,7 +19,7 @@ func (F *File) WriteOutput() error {
 		return err
 	}
 
-	err = ioutil.WriteFile(F.Filepath, F.FinalContent, 0644)
+	err = writeContent(F.Filepath, F.FinalContent, F.Symlink, F.FileMode(), F.Mode != 0)
 	if err != nil {
 		return err
 	}
@@ -40,7 +41,7 @@ func (F *File) WriteShadow(basedir string) error {
 		return err
 	}
 
-	err = ioutil.WriteFile(fn, F.RenderContent, 0644)
+	err = writeContent(fn, F.RenderContent, F.Symlink, F.FileMode(), F.Mode != 0)
 	if err != nil {
 		return err
 	}
@@ -48,3 +49,54 @@ func (F *File) WriteShadow(basedir string) error {
 	return nil
 }
 
+// ApplyMode updates the permissions of an existing output file
+// when they differ from the declared mode, content is left alone.
+// Without a declared mode, the user's permissions are kept.
+func (F *File) ApplyMode() error {
+	if F.Mode == 0 || F.Symlink != "" {
+		return nil
+	}
+
+	info, err := os.Lstat(F.Filepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if info.Mode().Perm() == F.Mode.Perm() {
+		return nil
+	}
+
+	return os.Chmod(F.Filepath, F.Mode)
+}
+
+// mode is used when the file is created, chmod applies it to an existing file too,
+// which is only done for declared modes so permissions users set are kept
+func writeContent(fn string, content []byte, symlink string, mode os.FileMode, chmod bool) error {
+	// symlinks and files replace each other
+	info, err := os.Lstat(fn)
+	if err == nil && (symlink != "" || info.Mode()&os.ModeSymlink != 0) {
+		err = os.Remove(fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	if symlink != "" {
+		return os.Symlink(symlink, fn)
+	}
+
+	err = ioutil.WriteFile(fn, content, mode)
+	if err != nil {
+		return err
+	}
+
+	if !chmod {
+		return nil
+	}
+
+	// WriteFile only sets the mode on creation
+	return os.Chmod(fn, mode)
+}
@@ -0,0 +1,117 @@
+package mod
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/hofstadter-io/hof/lib/mod/cache"
+	"github.com/hofstadter-io/hof/lib/mod/modder"
+)
+
+// SetOffline makes every modder use only the module cache
+func SetOffline(offline bool) {
+	modder.Offline = offline
+}
+
+// CachePath prints where modules are cached
+func CachePath() error {
+	fmt.Println(cache.LocalCacheBaseDir)
+	return nil
+}
+
+// CacheList prints the cached module versions matching the patterns, with their sizes.
+// The modules are on stdout, for scripts, and the total on stderr.
+func CacheList(patterns []string) error {
+	entries, err := cacheEntries(patterns)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, E := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", E.Lang, E, cache.FormatSize(E.Size))
+		total += E.Size
+	}
+	w.Flush()
+
+	fmt.Fprintf(os.Stderr, "%d modules, %s\n", len(entries), cache.FormatSize(total))
+	return nil
+}
+
+// CacheClean removes the cached module versions matching the patterns, or all of them,
+// which must be asked for, so a missing pattern does not empty the cache.
+// The removed modules are on stdout, for scripts, and the total on stderr.
+func CacheClean(patterns []string, all, dryRun bool) error {
+	if len(patterns) == 0 && !all {
+		return fmt.Errorf("give the modules to remove as patterns, or --all to remove every cached module")
+	}
+	if len(patterns) > 0 && all {
+		return fmt.Errorf("--all removes every cached module, it does not take patterns")
+	}
+
+	entries, err := cacheEntries(patterns)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, E := range entries {
+		fmt.Printf("removing %s %s (%s)\n", E.Lang, E, cache.FormatSize(E.Size))
+		total += E.Size
+		if dryRun {
+			continue
+		}
+		err = cache.Remove(E)
+		if err != nil {
+			return err
+		}
+	}
+
+	if dryRun {
+		fmt.Fprintf(os.Stderr, "would free %s\n", cache.FormatSize(total))
+	} else {
+		fmt.Fprintf(os.Stderr, "freed %s\n", cache.FormatSize(total))
+	}
+	return nil
+}
+
+// CacheVerify checks that the cached module versions matching the patterns are unchanged since they were fetched
+func CacheVerify(patterns []string) error {
+	entries, err := cacheEntries(patterns)
+	if err != nil {
+		return err
+	}
+
+	bad := 0
+	for _, E := range entries {
+		err = cache.Verify(E)
+		if err != nil {
+			fmt.Println(err)
+			bad++
+		}
+	}
+
+	if bad > 0 {
+		return fmt.Errorf("%d of %d cached modules failed verification, remove them with 'hof mod cache clean <module@version>'", bad, len(entries))
+	}
+
+	fmt.Printf("%d cached modules verified\n", len(entries))
+	return nil
+}
+
+func cacheEntries(patterns []string) ([]cache.Entry, error) {
+	all, err := cache.List()
+	if err != nil {
+		return nil, fmt.Errorf("While reading the module cache %s\n%w\n", cache.LocalCacheBaseDir, err)
+	}
+
+	var entries []cache.Entry
+	for _, E := range all {
+		if E.Match(patterns) {
+			entries = append(entries, E)
+		}
+	}
+	return entries, nil
+}
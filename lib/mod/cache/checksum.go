@@ -1,30 +1,39 @@
 package cache
 
 import (
-	"os"
-	"strings"
+	"fmt"
 
-	"golang.org/x/mod/sumdb/dirhash"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+
+	"github.com/hofstadter-io/hof/lib/yagu"
 )
 
-func Checksum(lang, mod, ver string) (string, error) {
+// Sums are h1: directory hashes, as in go.sum, over the module's files,
+// except .git, named by their path from the module root with a leading '/'.
+// The mod file is hashed on its own too, so it can be checked before the rest.
+// Fetched modules are hashed here, when loaded, vendored, and verified,
+// the hashing itself is yagu's, so there is one implementation of it.
 
-	flds := strings.Split(mod, "/")
-	remote := flds[0]
-	owner := flds[1]
-	repo := flds[2]
-	tag := ver
+// HashDir is the h1: hash of the module files in FS
+func HashDir(FS billy.Filesystem) (string, error) {
+	return yagu.BillyCalcHash(FS)
+}
 
-	dir := Outdir(lang, remote, owner, repo, tag)
-	// fmt.Println("Cache Checksum:", dir)
+// HashModFile is the h1: hash of the mod file in FS
+func HashModFile(modfile string, FS billy.Filesystem) (string, error) {
+	return yagu.BillyCalcFileHash(modfile, FS)
+}
 
-	_, err := os.Lstat(dir)
+// Checksum is the h1: hash of mod at ver in the cache
+func Checksum(lang, mod, ver string) (string, error) {
+	ok, err := Has(lang, mod, ver)
 	if err != nil {
 		return "", err
 	}
+	if !ok {
+		return "", fmt.Errorf("%s@%s is not in the cache", mod, ver)
+	}
 
-	h, err := dirhash.HashDir(dir, mod, dirhash.Hash1)
-
-	return h, err
+	return HashDir(osfs.New(Dir(lang, mod, ver)))
 }
-
@@ -5,9 +5,17 @@ import (
 	"path/filepath"
 )
 
+// CacheEnv overrides where modules are cached, for CI which keeps the cache between runs
+const CacheEnv = "HOF_MOD_CACHE"
+
 var LocalCacheBaseDir = ".hof/mods"
 
 func init() {
+	if d := os.Getenv(CacheEnv); d != "" {
+		LocalCacheBaseDir = d
+		return
+	}
+
 	d, err := os.UserConfigDir()
 	if err != nil {
 		return
@@ -2,20 +2,13 @@ package cache
 
 import (
 	"os"
-	"strings"
 
 	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-billy/v5/osfs"
 )
 
 func Load(lang, mod, ver string) (FS billy.Filesystem, err error) {
-	flds := strings.Split(mod, "/")
-	remote := flds[0]
-	owner := flds[1]
-	repo := flds[2]
-	tag := ver
-
-	dir := Outdir(lang, remote, owner, repo, tag)
+	dir := Dir(lang, mod, ver)
 
 	// fmt.Println("Cache Load:", dir)
 
@@ -30,3 +23,15 @@ func Load(lang, mod, ver string) (FS billy.Filesystem, err error) {
 
 	return FS, nil
 }
+
+// Has reports whether mod at ver is in the cache
+func Has(lang, mod, ver string) (bool, error) {
+	_, err := os.Lstat(Dir(lang, mod, ver))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,197 @@
+package cache
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"golang.org/x/mod/semver"
+)
+
+// Entry is a module version in the cache
+type Entry struct {
+	Lang    string
+	Module  string
+	Version string
+	Dir     string
+	Size    int64
+}
+
+func (E Entry) String() string {
+	return E.Module + "@" + E.Version
+}
+
+// Match reports whether the entry matches any of the patterns, which are
+// module paths, also matching the modules below them, or module@version.
+// No patterns match everything.
+func (E Entry) Match(patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if i := strings.LastIndex(p, "@"); i >= 0 {
+			if p[:i] == E.Module && p[i+1:] == E.Version {
+				return true
+			}
+			continue
+		}
+		p = strings.TrimSuffix(p, "/")
+		if E.Module == p || strings.HasPrefix(E.Module, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// List walks the cache for module versions, sorted by language, module, and version
+func List() ([]Entry, error) {
+	root := filepath.Join(LocalCacheBaseDir, "mod")
+
+	langs, err := ioutil.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, L := range langs {
+		if !L.IsDir() {
+			continue
+		}
+		ldir := filepath.Join(root, L.Name())
+
+		err := filepath.Walk(ldir, func(fn string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			// module paths do not have '@', so the first is a module version
+			if !info.IsDir() || !strings.Contains(info.Name(), "@") {
+				return nil
+			}
+			// an interrupted write
+			if strings.HasSuffix(info.Name(), ".tmp") {
+				return filepath.SkipDir
+			}
+
+			rel, err := filepath.Rel(ldir, fn)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			i := strings.LastIndex(rel, "@")
+
+			size, err := dirSize(fn)
+			if err != nil {
+				return err
+			}
+
+			entries = append(entries, Entry{
+				Lang:    L.Name(),
+				Module:  rel[:i],
+				Version: rel[i+1:],
+				Dir:     fn,
+				Size:    size,
+			})
+
+			return filepath.SkipDir
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Lang != b.Lang {
+			return a.Lang < b.Lang
+		}
+		if a.Module != b.Module {
+			return a.Module < b.Module
+		}
+		if c := semver.Compare(a.Version, b.Version); c != 0 {
+			return c < 0
+		}
+		return a.Version < b.Version
+	})
+
+	return entries, nil
+}
+
+// Remove deletes a module version and its recorded hash, holding its Lock so a
+// concurrent fetch does not see it half removed. The lock file is kept, removing it
+// would let another process lock a new file while one still waits on the old.
+func Remove(E Entry) error {
+	unlock, err := Lock(E.Lang, E.Module, E.Version)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = os.RemoveAll(E.Dir)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(E.Dir + ".h1")
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Verify rehashes a module version and compares it to the hash recorded when it was cached
+func Verify(E Entry) error {
+	data, err := ioutil.ReadFile(E.Dir + ".h1")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s %s: no recorded hash, it was cached by an older hof or the download was interrupted", E.Lang, E)
+		}
+		return err
+	}
+	want := strings.TrimSpace(string(data))
+
+	got, err := HashDir(osfs.New(E.Dir))
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", E.Lang, E, err)
+	}
+
+	if got != want {
+		return fmt.Errorf("%s %s: has been modified\n\tcached:   %s\n\trecorded: %s", E.Lang, E, got, want)
+	}
+
+	return nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(fn string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// FormatSize is a byte count for people, i.e. 1.5 MB
+func FormatSize(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
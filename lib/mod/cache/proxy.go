@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"golang.org/x/mod/semver"
+
+	"github.com/hofstadter-io/hof/lib/gotils/module"
+)
+
+// Module proxies speak the Go module proxy protocol
+//
+//   <proxy>/<module>/@v/list           versions, one per line
+//   <proxy>/<module>/@v/<version>.info {"Version": ..., "Time": ...}
+//   <proxy>/<module>/@v/<version>.mod  the mod file
+//   <proxy>/<module>/@v/<version>.zip  files prefixed with <module>@<version>/
+//
+// Module paths and versions are case encoded, and proxies may be http(s):// or file:// urls.
+
+// ErrNotFound is returned when a proxy does not have a module or version,
+// so the next proxy, or direct, can be tried
+var ErrNotFound = errors.New("not found")
+
+// proxyClient gives up on a stalled proxy, rather than hanging the fetch
+var proxyClient = &http.Client{
+	Timeout: 5 * time.Minute,
+	Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+	},
+}
+
+// FetchProxy downloads the zip for mod at ver from the proxy into the cache
+func FetchProxy(lang, mod, ver, proxy string) error {
+	data, err := proxyGet(proxy, mod, ver+".zip")
+	if err != nil {
+		return fmt.Errorf("While fetching %s@%s from %s\n%w\n", mod, ver, proxy, err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("While reading %s@%s zip from %s\n%w\n", mod, ver, proxy, err)
+	}
+
+	FS := memfs.New()
+	prefix := mod + "@" + ver + "/"
+	for _, f := range zr.File {
+		if !strings.HasPrefix(f.Name, prefix) {
+			return fmt.Errorf("Unexpected file %q in %s@%s zip from %s", f.Name, mod, ver, proxy)
+		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+
+		fn := strings.TrimPrefix(f.Name, prefix)
+		err = FS.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			return err
+		}
+		w, err := FS.Create(fn)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		w.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return Write(lang, mod, ver, FS)
+}
+
+// ProxyVersions lists the versions of mod the proxy has, lowest first
+func ProxyVersions(mod, proxy string) ([]string, error) {
+	data, err := proxyGet(proxy, mod, "list")
+	if err != nil {
+		return nil, fmt.Errorf("While listing %s from %s\n%w\n", mod, proxy, err)
+	}
+
+	var vers []string
+	for _, line := range strings.Split(string(data), "\n") {
+		ver := strings.TrimSpace(line)
+		if semver.IsValid(ver) {
+			vers = append(vers, ver)
+		}
+	}
+
+	sort.Slice(vers, func(i, j int) bool {
+		return semver.Compare(vers[i], vers[j]) < 0
+	})
+
+	return vers, nil
+}
+
+// ProxyInfo resolves a version, branch, commit, or HEAD to a version with the proxy's .info
+func ProxyInfo(mod, query, proxy string) (string, error) {
+	data, err := proxyGet(proxy, mod, query+".info")
+	if err != nil {
+		return "", fmt.Errorf("While resolving %s@%s from %s\n%w\n", mod, query, proxy, err)
+	}
+
+	var info struct {
+		Version string
+	}
+	err = json.Unmarshal(data, &info)
+	if err != nil {
+		return "", fmt.Errorf("While reading %s@%s info from %s\n%w\n", mod, query, proxy, err)
+	}
+	if !semver.IsValid(info.Version) {
+		return "", fmt.Errorf("Invalid version %q for %s@%s from %s", info.Version, mod, query, proxy)
+	}
+
+	return info.Version, nil
+}
+
+// proxyGet fetches <proxy>/<module>/@v/<file>, file is a version and extension, or list
+func proxyGet(proxy, mod, file string) ([]byte, error) {
+	encMod, err := module.EncodePath(mod)
+	if err != nil {
+		return nil, err
+	}
+	if file != "list" {
+		ext := filepath.Ext(file)
+		encVer, err := module.EncodeVersion(strings.TrimSuffix(file, ext))
+		if err != nil {
+			return nil, err
+		}
+		file = encVer + ext
+	}
+
+	url := strings.TrimSuffix(proxy, "/") + "/" + encMod + "/@v/" + file
+
+	if strings.HasPrefix(url, "file://") {
+		data, err := ioutil.ReadFile(filepath.FromSlash(strings.TrimPrefix(url, "file://")))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("%s: %w", url, ErrNotFound)
+			}
+			return nil, err
+		}
+		return data, nil
+	}
+
+	resp, err := proxyClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%s: %w", url, ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s: %s\n%s", url, resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	return data, nil
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/hofstadter-io/hof/lib/yagu/repos/git"
+)
+
+// Pseudo-versions follow the Go rules, so untagged commits still sort
+// after the tag they build on and before the next release
+//
+//   v0.0.0-yyyymmddhhmmss-abcdef123456       no tag in the history
+//   vX.Y.(Z+1)-0.yyyymmddhhmmss-abcdef123456 after the release vX.Y.Z
+//   vX.Y.Z-pre.0.yyyymmddhhmmss-abcdef123456 after the prerelease vX.Y.Z-pre
+
+const pseudoTimeFormat = "20060102150405"
+
+var pseudoVersionRE = regexp.MustCompile(`^v[0-9]+\.(0\.0-|\d+\.\d+-([^+]*\.)?0\.)\d{14}-[A-Za-z0-9]+(\+incompatible)?$`)
+
+// PseudoVersion is the version for commit rev at time t, base is the greatest earlier tag or ""
+func PseudoVersion(base string, t time.Time, rev string) string {
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	segment := t.UTC().Format(pseudoTimeFormat) + "-" + rev
+
+	// modules without a major suffix can only build on v0 and v1
+	major := semver.Major(base)
+	if base == "" || (major != "v0" && major != "v1") {
+		return "v0.0.0-" + segment
+	}
+
+	base = strings.TrimSuffix(base, semver.Build(base))
+	if semver.Prerelease(base) != "" {
+		return base + ".0." + segment
+	}
+
+	var maj, min, patch int
+	fmt.Sscanf(semver.Canonical(base), "v%d.%d.%d", &maj, &min, &patch)
+	return fmt.Sprintf("v%d.%d.%d-0.%s", maj, min, patch+1, segment)
+}
+
+// IsPseudoVersion reports whether v is a pseudo-version
+func IsPseudoVersion(v string) bool {
+	return strings.Count(v, "-") >= 2 && semver.IsValid(v) && pseudoVersionRE.MatchString(v)
+}
+
+// PseudoVersionRev is the abbreviated commit of a pseudo-version
+func PseudoVersionRev(v string) string {
+	v = strings.TrimSuffix(v, "+incompatible")
+	return v[strings.LastIndex(v, "-")+1:]
+}
+
+// ResolveVersion turns a branch, commit, or "" for the default branch of the git remote
+// into a version, the greatest tag when the commit has one, otherwise a pseudo-version
+func ResolveVersion(url, query string) (string, error) {
+	info, err := git.ResolveRef(url, query)
+	if err != nil {
+		return "", err
+	}
+
+	if len(info.Tags) > 0 {
+		best := info.Tags[0]
+		for _, t := range info.Tags[1:] {
+			if semver.Compare(t, best) > 0 {
+				best = t
+			}
+		}
+		return best, nil
+	}
+
+	return PseudoVersion(info.Base, info.Time, info.Hash), nil
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/hofstadter-io/hof/lib/yagu/repos/git"
+)
+
+// RemoteVersions lists the semver tags of the git remote at url, lowest first.
+// Tags without the leading 'v' are included with it.
+func RemoteVersions(url string) ([]string, error) {
+	refs, err := git.ListRemote(url)
+	if err != nil {
+		return nil, fmt.Errorf("While listing tags for %s\n%w\n", url, err)
+	}
+
+	var vers []string
+	for _, ref := range refs {
+		name := ref.Name()
+		if !name.IsTag() {
+			continue
+		}
+		ver := name.Short()
+		if !strings.HasPrefix(ver, "v") {
+			ver = "v" + ver
+		}
+		if semver.IsValid(ver) {
+			vers = append(vers, ver)
+		}
+	}
+
+	sort.Slice(vers, func(i, j int) bool {
+		return semver.Compare(vers[i], vers[j]) < 0
+	})
+
+	return vers, nil
+}
+
+// Latest is the greatest release, or prerelease when there are no releases, vers are sorted
+func Latest(vers []string) string {
+	if len(vers) == 0 {
+		return ""
+	}
+
+	for i := len(vers) - 1; i >= 0; i-- {
+		if semver.Prerelease(vers[i]) == "" {
+			return vers[i]
+		}
+	}
+	return vers[len(vers)-1]
+}
@@ -1,32 +1,73 @@
 package cache
 
 import (
-	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	"github.com/go-git/go-billy/v5"
 
+	"github.com/hofstadter-io/hof/lib/gotils/lockedfile"
 	"github.com/hofstadter-io/hof/lib/yagu"
 )
 
-func Outdir(lang, remote, owner, repo, tag string) string {
+// Dir is where a module version is kept in the cache
+func Dir(lang, mod, ver string) string {
 	outdir := filepath.Join(
 		LocalCacheBaseDir,
 		"mod",
 		lang,
-		remote,
-		owner,
-		repo + "@" + tag,
+		filepath.FromSlash(mod)+"@"+ver,
 	)
 	return outdir
 }
 
-func Write(lang, remote, owner, repo, tag string, FS billy.Filesystem) error {
-	fmt.Printf("Saving %s mod %s/%s/%s@%s\n", lang, remote, owner, repo, tag)
-	outdir := Outdir(lang, remote, owner, repo, tag)
-	err := yagu.Mkdir(outdir)
+// Write saves mod at ver into the cache, callers hold its Lock. It is written
+// beside its final directory and renamed, so readers never see part of a module.
+func Write(lang, mod, ver string, FS billy.Filesystem) error {
+	outdir := Dir(lang, mod, ver)
+	tmpdir := outdir + ".tmp"
+
+	// left by an interrupted write
+	err := os.RemoveAll(tmpdir)
+	if err != nil {
+		return err
+	}
+
+	err = yagu.Mkdir(tmpdir)
+	if err != nil {
+		return err
+	}
+	err = yagu.BillyWriteDirToOS(tmpdir, "/", FS)
+	if err != nil {
+		return err
+	}
+
+	// recorded for 'hof mod cache verify'
+	h, err := HashDir(FS)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(HashFile(lang, mod, ver), []byte(h+"\n"), 0644)
 	if err != nil {
 		return err
 	}
-	return yagu.BillyWriteDirToOS(outdir, "/", FS)
+
+	return os.Rename(tmpdir, outdir)
+}
+
+// Lock holds the lock for mod at ver in the cache, across processes, until unlock is called.
+// Check the cache again once it is held, the module may have been written meanwhile.
+func Lock(lang, mod, ver string) (unlock func(), err error) {
+	fn := Dir(lang, mod, ver) + ".lock"
+	err = yagu.Mkdir(filepath.Dir(fn))
+	if err != nil {
+		return nil, err
+	}
+	return lockedfile.MutexAt(fn).Lock()
+}
+
+// HashFile is where the hash of a module version is recorded when it is cached
+func HashFile(lang, mod, ver string) string {
+	return Dir(lang, mod, ver) + ".h1"
 }
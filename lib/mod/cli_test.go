@@ -2,8 +2,10 @@ package mod_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/hofstadter-io/hof/lib/gotils/goproxytest"
 	"github.com/hofstadter-io/hof/lib/yagu"
 	"github.com/hofstadter-io/hof/script/runtime"
 )
@@ -35,3 +37,29 @@ func TestModBugs(t *testing.T) {
 		WorkdirRoot: ".workdir/bugs",
 	})
 }
+
+func TestModProxy(t *testing.T) {
+	srv, err := goproxytest.NewServer(filepath.Join("testdata", "proxy", "mod"), "")
+	if err != nil {
+		t.Fatalf("cannot start proxy: %v", err)
+	}
+
+	yagu.Mkdir(".workdir/proxy")
+	runtime.Run(t, runtime.Params{
+		Setup: func(env *runtime.Env) error {
+			// a fresh module cache for each script
+			wd, err := filepath.Abs(env.WorkDir)
+			if err != nil {
+				return err
+			}
+			env.Vars = append(env.Vars,
+				"HOF_MOD_PROXY="+srv.URL+",off",
+				"XDG_CONFIG_HOME="+filepath.Join(wd, ".config"),
+			)
+			return envSetup(env)
+		},
+		Dir: "testdata/proxy",
+		Glob: "*.txt",
+		WorkdirRoot: ".workdir/proxy",
+	})
+}
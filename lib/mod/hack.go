@@ -56,19 +56,19 @@ func Hack(lang string, args []string) error {
 
 		// fmt.Println("GOT HERE 1")
 
-		err = cache.Write("hof", "github.com", owner, repo, tag, FS)
+		err = cache.Write("hof", strings.Join([]string{"github.com", owner, repo}, "/"), tag, FS)
 		if err != nil {
 			return fmt.Errorf("While writing to cache\n%w\n", err)
 		}
 
 		// fmt.Println("GOT HERE 2")
 
-		dirhash, err := yagu.BillyCalcHash(FS)
+		dirhash, err := cache.HashDir(FS)
 		if err != nil {
 			return fmt.Errorf("While calculating dir hash\n%w\n", err)
 		}
 
-		modhash, err := yagu.BillyCalcFileHash("cue.mods", FS)
+		modhash, err := cache.HashModFile("cue.mods", FS)
 		if err != nil {
 			return fmt.Errorf("While calculating mod hash\n%w\n", err)
 		}
@@ -23,6 +23,9 @@ const LOCAL_MVS_CONFIG = ".mvsconfig.cue"
 var (
 	// Default known modderr
 	LangModderMap = langs.DefaultModders
+
+	// configs are merged with the defaults, which only works within one runtime
+	cueRuntime cue.Runtime
 )
 
 const knownLangMessage = `
@@ -107,7 +110,7 @@ func LangInfo(lang string) (string, error) {
 func InitLangs() {
 	var err error
 
-	rt := cue.Runtime{}
+	rt := &cueRuntime
 	cueSpec, err := rt.Compile("spec.cue", langs.ModderSpec)
 	if err != nil {
 		panic(err)
@@ -172,7 +175,7 @@ func initFromFile(filepath string) error {
 	var mdrMap map[string]*modder.Modder
 
 	// Compile the config into cue
-	rt := cue.Runtime{}
+	rt := &cueRuntime
 	i, err := rt.Compile(filepath, string(bytes))
 	if err != nil {
 		return err
@@ -20,5 +20,17 @@ cue: {
 		"/.git/**",
 		"**/cue.mod/pkg/**",
 	]
+	IntrospectIncludeGlobs: [...string] | *[
+		"**/*.cue",
+	]
+	IntrospectExcludeGlobs: [...string] | *[
+		"/.git/**",
+		"**/cue.mod/pkg/**",
+	]
+	// single imports, and import blocks
+	IntrospectExtractRegex: [...string] | *[
+		"(?m)^import\\s+(?:[\\w#$]+\\s+)?(\"[^\"]+\")",
+		"(?ms)^import\\s*\\((.*?)\\)",
+	]
 }
 `
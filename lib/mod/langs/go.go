@@ -10,6 +10,8 @@ go: {
 	MappingFile:   string | *"vendor/modules.txt",
 	CommandInit:   [...[...string]] | *[["go", "mod", "init"]],
 	CommandGraph:  [...[...string]] | *[["go", "mod", "graph"]],
+	CommandGet:    [...[...string]] | *[["go", "get"]],
+	CommandWhy:    [...[...string]] | *[["go", "mod", "why"]],
 	CommandTidy:   [...[...string]] | *[["go", "mod", "tidy"]],
 	CommandVendor: [...[...string]] | *[["go", "mod", "vendor"]],
 	CommandVerify: [...[...string]] | *[["go", "mod", "verify"]],
@@ -14,6 +14,9 @@ var ModderSpec = `
 		NoLoad?: bool,
 		CommandInit?: [...[...string]],
 		CommandGraph?: [...[...string]],
+		CommandGet?: [...[...string]],
+		CommandList?: [...[...string]],
+		CommandWhy?: [...[...string]],
 		CommandTidy?: [...[...string]],
 		CommandVendor?: [...[...string]],
 		CommandVerify?: [...[...string]],
@@ -41,6 +44,11 @@ var ModderSpec = `
 		IntrospectExtractRegex?: [...string],
 
 		PackageManagerDefaultPrefix?: string,
+
+		Remotes?: {
+			[string]: string
+		},
+		Proxy?: string,
 	}
 }
 `
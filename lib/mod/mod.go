@@ -29,8 +29,6 @@ func ProcessLangs(method string, langs []string) error {
 
 	for _, lang := range langs {
 		switch method {
-		case "graph":
-			err = Graph(lang)
 		case "status":
 			err = Status(lang)
 		case "tidy":
@@ -59,12 +57,83 @@ func Init(lang, module string) error {
 	return mdr.Init(module)
 }
 
-func Graph(lang string) error {
+// GraphLangs prints the dependency graph for each language, format is one of text, dot, json
+func GraphLangs(langs []string, format string) error {
+	if len(langs) == 0 {
+		langs = DiscoverLangs()
+	}
+
+	for _, lang := range langs {
+		err := Graph(lang, format)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func Graph(lang, format string) error {
+	mdr, err := getModder(lang)
+	if err != nil {
+		return err
+	}
+	return mdr.Graph(format)
+}
+
+// Why prints the shortest chain of requires to each package, or module with modules,
+// lang is discovered when empty
+func Why(lang string, targets []string, modules bool) error {
+	if lang == "" {
+		langs := DiscoverLangs()
+		if len(langs) != 1 {
+			return fmt.Errorf("Found %d languages %v, use --lang to choose one", len(langs), langs)
+		}
+		lang = langs[0]
+	}
+
 	mdr, err := getModder(lang)
 	if err != nil {
 		return err
 	}
-	return mdr.Graph()
+	return mdr.Why(targets, modules)
+}
+
+// Get updates requires with module@version queries, lang is discovered when empty
+func Get(lang string, queries []string) error {
+	if lang == "" {
+		langs := DiscoverLangs()
+		if len(langs) != 1 {
+			return fmt.Errorf("Found %d languages %v, use --lang to choose one", len(langs), langs)
+		}
+		lang = langs[0]
+	}
+
+	mdr, err := getModder(lang)
+	if err != nil {
+		return err
+	}
+	return mdr.Get(queries)
+}
+
+// ListLangs prints the build list for each language, upgrades adds available upgrades
+func ListLangs(langs []string, upgrades bool) error {
+	if len(langs) == 0 {
+		langs = DiscoverLangs()
+	}
+
+	for _, lang := range langs {
+		mdr, err := getModder(lang)
+		if err != nil {
+			return err
+		}
+		err = mdr.List(upgrades)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func Status(lang string) error {
@@ -8,6 +8,7 @@ import (
 
 	"cuelang.org/go/cue"
 
+	"github.com/hofstadter-io/hof/lib/gotils/par"
 	"github.com/hofstadter-io/hof/lib/yagu"
 )
 
@@ -17,51 +18,61 @@ import (
 type Modder struct {
 	// MetaConfiguration
 	Name    string `yaml:"Name"`
-	Version string `yaml:"Version",omitempty`
+	Version string `yaml:"Version,omitempty"`
 
 	// Module information
-	ModFile     string `yaml:"ModFile",omitempty`
-	SumFile     string `yaml:"SumFile",omitempty`
-	ModsDir     string `yaml:"ModsDir",omitempty`
-	MappingFile string `yaml:"MappingFile",omitempty`
+	ModFile     string `yaml:"ModFile,omitempty"`
+	SumFile     string `yaml:"SumFile,omitempty"`
+	ModsDir     string `yaml:"ModsDir,omitempty"`
+	MappingFile string `yaml:"MappingFile,omitempty"`
 
 	// Commands override default, configuragble processing
 	// for things like golang
-	NoLoad        bool       `yaml:"NoLoad",omitempty`
-	CommandInit   [][]string `yaml:"CommandInit",omitempty`
-	CommandGraph  [][]string `yaml:"CommandGraph",omitempty`
-	CommandTidy   [][]string `yaml:"CommandTidy",omitempty`
-	CommandVendor [][]string `yaml:"CommandVendor",omitempty`
-	CommandVerify [][]string `yaml:"CommandVerify",omitempty`
-	CommandStatus [][]string `yaml:"CommandStatus",omitempty`
+	NoLoad        bool       `yaml:"NoLoad,omitempty"`
+	CommandInit   [][]string `yaml:"CommandInit,omitempty"`
+	CommandGraph  [][]string `yaml:"CommandGraph,omitempty"`
+	CommandGet    [][]string `yaml:"CommandGet,omitempty"`
+	CommandList   [][]string `yaml:"CommandList,omitempty"`
+	CommandWhy    [][]string `yaml:"CommandWhy,omitempty"`
+	CommandTidy   [][]string `yaml:"CommandTidy,omitempty"`
+	CommandVendor [][]string `yaml:"CommandVendor,omitempty"`
+	CommandVerify [][]string `yaml:"CommandVerify,omitempty"`
+	CommandStatus [][]string `yaml:"CommandStatus,omitempty"`
 
 	// Init related fields
 	// we need to create things like directories and files beyond the
-	InitTemplates    map[string]string `yaml:"InitTemplates",omitempty`
-	InitPreCommands  [][]string        `yaml:"InitPreCommands",omitempty`
-	InitPostCommands [][]string        `yaml:"InitPostCommands",omitempty`
+	InitTemplates    map[string]string `yaml:"InitTemplates,omitempty"`
+	InitPreCommands  [][]string        `yaml:"InitPreCommands,omitempty"`
+	InitPostCommands [][]string        `yaml:"InitPostCommands,omitempty"`
 
 	// Vendor related fields
 	// filesystem globs for discovering files we should copy over
-	VendorIncludeGlobs []string `yaml:"VendorIncludeGlobs",omitempty`
-	VendorExcludeGlobs []string `yaml:"VendorExcludeGlobs",omitempty`
+	VendorIncludeGlobs []string `yaml:"VendorIncludeGlobs,omitempty"`
+	VendorExcludeGlobs []string `yaml:"VendorExcludeGlobs,omitempty"`
 	// Any files we need to generate
-	VendorTemplates    map[string]string `yaml:"VendorTemplates",omitempty`
-	VendorPreCommands  [][]string        `yaml:"VendorPreCommands",omitempty`
-	VendorPostCommands [][]string        `yaml:"VendorPostCommands",omitempty`
+	VendorTemplates    map[string]string `yaml:"VendorTemplates,omitempty"`
+	VendorPreCommands  [][]string        `yaml:"VendorPreCommands,omitempty"`
+	VendorPostCommands [][]string        `yaml:"VendorPostCommands,omitempty"`
 
 	// Some more vendor controls
-	ManageFileOnly       bool `yaml:"ManageFileOnly",omitempty`
-	SymlinkLocalReplaces bool `yaml:"SymlinkLocalReplaces",omitempty`
+	ManageFileOnly       bool `yaml:"ManageFileOnly,omitempty"`
+	SymlinkLocalReplaces bool `yaml:"SymlinkLocalReplaces,omitempty"`
 
 	// Introspection Configuration(s)
 	// filesystem globs for discovering files we should introspect
 	// regexs for extracting package information
-	IntrospectIncludeGlobs []string `yaml:"IntrospectIncludeGlobs",omitempty`
-	IntrospectExcludeGlobs []string `yaml:"IntrospectExcludeGlobs",omitempty`
-	IntrospectExtractRegex []string `yaml:"IntrospectExtractRegex",omitempty`
+	IntrospectIncludeGlobs []string `yaml:"IntrospectIncludeGlobs,omitempty"`
+	IntrospectExcludeGlobs []string `yaml:"IntrospectExcludeGlobs,omitempty"`
+	IntrospectExtractRegex []string `yaml:"IntrospectExtractRegex,omitempty"`
 
-	PackageManagerDefaultPrefix string `yaml:"PackageManagerDefaultPrefix",omitempty`
+	PackageManagerDefaultPrefix string `yaml:"PackageManagerDefaultPrefix,omitempty"`
+
+	// Git remotes for module path prefixes, the default is https://<module>
+	// i.e. "git.example.com/": "ssh://git@git.example.com/" or "local.dev/": "file:///srv/git/"
+	Remotes map[string]string `yaml:"Remotes,omitempty"`
+
+	// Module proxies, see ModProxy
+	Proxy string `yaml:"Proxy,omitempty"`
 
 	// filesystem
 	FS billy.Filesystem `yaml:"-"`
@@ -75,6 +86,12 @@ type Modder struct {
 	// module writers can then have local control over how their module is handeled during vendoring
 	depsMap map[string]*Module `yaml:"-"`
 
+	// every require seen while resolving, selected or not
+	edges []Edge `yaml:"-"`
+
+	// loaded modules by require, so each is fetched and loaded once
+	loads *par.Cache `yaml:"-"`
+
 	// compiled cue, used for merging
 	CueInstance *cue.Instance `yaml:"-"`
 }
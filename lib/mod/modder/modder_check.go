@@ -1,122 +1,106 @@
 package modder
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"path"
-	"strings"
 
 	"github.com/go-git/go-billy/v5/osfs"
 
+	"github.com/hofstadter-io/hof/lib/mod/cache"
 	"github.com/hofstadter-io/hof/lib/mod/parse/sumfile"
 	"github.com/hofstadter-io/hof/lib/yagu"
 )
 
-func (mdr *Modder) PartitionSumEntries() ([]string, []string, []string, error) {
-	present := []string{}
-	missing := []string{}
-	local := []string{}
-
-	mod := mdr.module
-	sf := mod.SumFile
-	if sf == nil {
-		return nil, nil, nil, fmt.Errorf("No sum file %q for %s, run 'mvs vendor [%s]' to fix.", mdr.SumFile, mdr.Name, mdr.Name)
+// sumVersions are the sum file keys for a dependency's files and mod file,
+// by the path it is imported as
+func (mdr *Modder) sumVersions(m *Module) (sumfile.Version, sumfile.Version) {
+	dver := sumfile.Version{
+		Path:    m.Module,
+		Version: m.Version,
 	}
-
-	for path, R := range mod.SelfDeps {
-		// local replace?
-		if strings.HasPrefix(R.NewPath, ".") {
-			local = append(local, path)
-			continue
-		}
-
-		ver := sumfile.Version{
-			Path:    path,
-			Version: R.NewVersion,
-		}
-
-		_, ok := sf.Mods[ver]
-		if ok {
-			present = append(present, path)
-		} else {
-			missing = append(missing, path)
-		}
+	mver := sumfile.Version{
+		Path:    m.Module,
+		Version: path.Join(m.Version, mdr.ModFile),
 	}
-
-	return present, missing, local, nil
+	return dver, mver
 }
 
-func (mdr *Modder) CompareSumEntryToVendor(R Replace) error {
+// checkSums verifies a fetched module against the sum file. The mod file is checked first,
+// as it is read to find more dependencies. Modules without sums are accepted,
+// their sums are added when the sum file is written.
+func (mdr *Modder) checkSums(m *Module) error {
 	sf := mdr.module.SumFile
-
-	// sumfile ver from Replace
-	dirver := sumfile.Version{
-		Path:    R.NewPath,
-		Version: R.NewVersion,
-	}
-	modver := sumfile.Version{
-		Path:    R.NewPath,
-		Version: path.Join(dirver.Version, mdr.ModFile),
+	if sf == nil {
+		return nil
 	}
 
-	// sumfile dirhash
-	d, ok := sf.Mods[dirver]
-	if !ok {
-		merr := fmt.Errorf("Missing module dirhash in sumfile for '%v' from modfile entry '%v'", dirver, R)
-		// mdr.errors = append(mdr.errors, merr)
-		return merr
+	dver, mver := mdr.sumVersions(m)
+
+	if want, ok := sf.Mods[mver]; ok {
+		got, err := cache.HashModFile(mdr.ModFile, m.FS)
+		if err != nil {
+			return fmt.Errorf("While calculating mod hash for %s@%s\n%w\n", m.Module, mver.Version, err)
+		}
+		if got != want[0] {
+			return mdr.checksumError(m, mver.Version, got, want[0])
+		}
 	}
-	sumDirhash := d[0]
 
-	// sumfile modhash
-	m, ok := sf.Mods[modver]
-	if !ok {
-		merr := fmt.Errorf("Missing module modhash in sumfile for '%v' from modfile entry '%v'", modver, R)
-		// mdr.errors = append(mdr.errors, merr)
-		return merr
+	if want, ok := sf.Mods[dver]; ok {
+		got, err := cache.HashDir(m.FS)
+		if err != nil {
+			return fmt.Errorf("While calculating dir hash for %s@%s\n%w\n", m.Module, m.Version, err)
+		}
+		if got != want[0] {
+			return mdr.checksumError(m, m.Version, got, want[0])
+		}
 	}
-	sumModhash := m[0]
 
-	// fmt.Println("SUMHASH", sumDirhash, sumModhash)
+	return nil
+}
 
-	// load vendor copy, oldpath because that is how it will be imported
-	rpath := R.OldPath
-	if R.OldPath == "" {
-		rpath = R.NewPath
-	}
-	vpath := path.Join(mdr.ModsDir, rpath)
-	// fmt.Println("VPATH", vpath)
-	FS := osfs.New(vpath)
+// ver is the module version, or the version and mod file
+func (mdr *Modder) checksumError(m *Module, ver, got, want string) error {
+	return fmt.Errorf(`verifying %s: checksum mismatch
+	downloaded: %s
+	%s: %s
+
+SECURITY ERROR
+This download does NOT match the one recorded in %s.
+The module may have been tampered with, upstream or in the cache at
+	%s
+If the change is expected, remove the cached copy and the entry in %s.
+`, m.Module+"@"+ver, got, mdr.SumFile, want, mdr.SumFile, cache.Dir(mdr.Name, m.Module, m.Version), mdr.SumFile)
+}
 
-	// Calc hashes for vendor from billy
-	vdrDirhash, err := yagu.BillyCalcHash(FS)
-	if err != nil {
-		merr := fmt.Errorf("While calculating vendor dirhash for '%v' from '%v'\n%w\n", dirver, R, err)
-		// mdr.errors = append(mdr.errors, merr)
-		return merr
+// CompareModuleToVendor checks that the vendored files of a module
+// are unchanged copies of the module's files, which were verified when loaded
+func (mdr *Modder) CompareModuleToVendor(m *Module) error {
+	vpath := path.Join(mdr.ModsDir, m.Module)
+	if _, err := os.Stat(vpath); err != nil {
+		return fmt.Errorf("%s@%s is not vendored in %s", m.Module, m.Version, vpath)
 	}
+	VFS := osfs.New(vpath)
 
-	vdrModhash, err := yagu.BillyCalcFileHash(mdr.ModFile, FS)
+	files, err := yagu.BillyFilenames("/", VFS)
 	if err != nil {
-		merr := fmt.Errorf("While calculating vendor modhash for '%v' from '%v'\n%w\n", modver, R, err)
-		// mdr.errors = append(mdr.errors, merr)
-		return merr
+		return fmt.Errorf("While reading vendored %s\n%w\n", vpath, err)
 	}
-	// fmt.Println("VDRHASH", vdrDirhash, vdrModhash)
 
-	mismatch := false
-	if sumDirhash != vdrDirhash {
-		// merr := fmt.Errorf("Mismatched dir hashes in sumfile for '%v' from modfile entry '%v'", dirver, R)
-		// mdr.errors = append(mdr.errors, merr)
-		mismatch = true
-	}
-	if sumModhash != vdrModhash {
-		// merr := fmt.Errorf("Mismatched modfile hashes in sumfile for '%v' from modfile entry '%v'", modver, R)
-		// mdr.errors = append(mdr.errors, merr)
-		mismatch = true
-	}
-
-	if mismatch {
-		return fmt.Errorf("Errors with vendor integrity")
+	for _, fn := range files {
+		vdr, err := yagu.BillyReadAll(fn, VFS)
+		if err != nil {
+			return err
+		}
+		mod, err := yagu.BillyReadAll(fn, m.FS)
+		if err != nil {
+			return fmt.Errorf("Vendored file %s is not part of %s@%s", path.Join(vpath, fn), m.Module, m.Version)
+		}
+		if !bytes.Equal(vdr, mod) {
+			return fmt.Errorf("Vendored file %s does not match %s@%s, it was modified after vendoring", path.Join(vpath, fn), m.Module, m.Version)
+		}
 	}
 
 	return nil
@@ -136,7 +120,7 @@ func (mdr *Modder) CompareLocalReplaceToVendor(R Replace) error {
 		return merr
 	}
 
-	localModhash, err := yagu.BillyCalcFileHash(mdr.ModFile, LFS)
+	localModhash, err := cache.HashModFile(mdr.ModFile, LFS)
 	if err != nil {
 		merr := fmt.Errorf("While calculating local modhash for '%#+v'\n%w\n", R, err)
 		mdr.errors = append(mdr.errors, merr)
@@ -152,7 +136,7 @@ func (mdr *Modder) CompareLocalReplaceToVendor(R Replace) error {
 		return merr
 	}
 
-	vdrModhash, err := yagu.BillyCalcFileHash(mdr.ModFile, VFS)
+	vdrModhash, err := cache.HashModFile(mdr.ModFile, VFS)
 	if err != nil {
 		merr := fmt.Errorf("While calculating vendor modhash for '%#+v'\n%w\n", R, err)
 		mdr.errors = append(mdr.errors, merr)
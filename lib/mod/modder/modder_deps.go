@@ -2,10 +2,15 @@ package modder
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"sync/atomic"
 
 	"golang.org/x/mod/semver"
 
+	"github.com/hofstadter-io/hof/lib/gotils/par"
+	"github.com/hofstadter-io/hof/lib/mod/cache"
 	"github.com/hofstadter-io/hof/lib/yagu/repos/git"
 )
 
@@ -29,6 +34,111 @@ func (mdr *Modder) LoadRootDeps() error {
 	return nil
 }
 
+// FetchWorkers bounds how many modules are fetched and loaded at once
+var FetchWorkers = 8
+
+// LoadDeps fetches and loads the root module's dependencies, a level at a time,
+// selecting the minimal versions into the depsMap. The modules of a level are
+// fetched concurrently and then merged in order, so the result does not depend
+// on which finishes first.
+func (mdr *Modder) LoadDeps() error {
+	level := selfDeps(mdr.module)
+	for _, R := range level {
+		mdr.addEdge(mdr.module, R)
+	}
+
+	for len(level) > 0 {
+		var next []Replace
+		for _, m := range mdr.loadLevel(level) {
+			// nil when loading failed, the error is recorded
+			if m == nil || !mdr.MvsMergeDependency(m) {
+				continue
+			}
+			for _, R := range selfDeps(m) {
+				mdr.addEdge(m, R)
+				next = append(next, R)
+			}
+		}
+		level = next
+	}
+
+	return mdr.CheckForErrors()
+}
+
+// selfDeps are a module's requires, sorted by path
+func selfDeps(m *Module) []Replace {
+	var paths []string
+	for path := range m.SelfDeps {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	deps := make([]Replace, 0, len(paths))
+	for _, path := range paths {
+		deps = append(deps, m.SelfDeps[path])
+	}
+	return deps
+}
+
+type loadResult struct {
+	m   *Module
+	err error
+}
+
+// loadLevel fetches and loads the modules for the requires with FetchWorkers,
+// in the order of the requires. Each require is only loaded once per modder.
+func (mdr *Modder) loadLevel(level []Replace) []*Module {
+	// progress is printed for the modules which need fetching,
+	// on stderr to keep the output of graph and why clean
+	fetching := map[Replace]bool{}
+	for _, R := range level {
+		if mdr.loads.Get(R) != nil || strings.HasPrefix(R.NewPath, "./") || strings.HasPrefix(R.NewPath, "../") {
+			continue
+		}
+		if ok, _ := cache.Has(mdr.Name, R.NewPath, R.NewVersion); !ok {
+			fetching[R] = true
+		}
+	}
+	total := len(fetching)
+	if total > 0 {
+		fmt.Fprintf(os.Stderr, "Fetching %d %s modules\n", total, mdr.Name)
+	}
+	var done int32
+
+	var work par.Work
+	for _, R := range level {
+		work.Add(R)
+	}
+	work.Do(FetchWorkers, func(item interface{}) {
+		R := item.(Replace)
+		mdr.loads.Do(R, func() interface{} {
+			m, err := mdr.VendorDep(R)
+			if fetching[R] {
+				n := atomic.AddInt32(&done, 1)
+				fmt.Fprintf(os.Stderr, "  [%d/%d] %s@%s\n", n, total, R.NewPath, R.NewVersion)
+			}
+			return loadResult{m, err}
+		})
+	})
+
+	// errors are recorded once, in order
+	mods := make([]*Module, 0, len(level))
+	seen := map[Replace]bool{}
+	for _, R := range level {
+		res := mdr.loads.Get(R).(loadResult)
+		if res.err != nil {
+			if !seen[R] {
+				mdr.errors = append(mdr.errors, res.err)
+			}
+			seen[R] = true
+			continue
+		}
+		mods = append(mods, res.m)
+	}
+
+	return mods
+}
+
 // This sets or overwrites the module
 func (mdr *Modder) ReplaceDependency(m *Module) error {
 	// Don't add the root module to the dependencies
@@ -42,10 +152,11 @@ func (mdr *Modder) ReplaceDependency(m *Module) error {
 }
 
 // If not set, justs adds. If set, takes the one with the greater version.
-func (mdr *Modder) MvsMergeDependency(m *Module) error {
+// It reports whether m was selected, so its dependencies need loading.
+func (mdr *Modder) MvsMergeDependency(m *Module) bool {
 	// Don't add the root module to the dependencies
 	if mdr.module.Module == m.Module {
-		return nil
+		return false
 	}
 
 	// check for existing module
@@ -58,7 +169,7 @@ func (mdr *Modder) MvsMergeDependency(m *Module) error {
 		// check local replace
 		if strings.HasPrefix(e.ReplaceModule, ".") {
 			// do nothing
-			return nil
+			return false
 		}
 
 		// check remote replace
@@ -67,7 +178,7 @@ func (mdr *Modder) MvsMergeDependency(m *Module) error {
 				// check version, is what we have a newer version?
 				if semver.Compare(e.ReplaceVersion, m.ReplaceVersion) >= 0 {
 					// do nothing, only 1/4 cases
-					return nil
+					return false
 				}
 			}
 			// all other cases, want to update module
@@ -75,7 +186,7 @@ func (mdr *Modder) MvsMergeDependency(m *Module) error {
 			// check version, is what we have a newer version?
 			if semver.Compare(e.Version, m.Version) >= 0 {
 				// do nothing
-				return nil
+				return false
 			}
 		}
 
@@ -85,15 +196,8 @@ func (mdr *Modder) MvsMergeDependency(m *Module) error {
 
 	// fmt.Printf("Merge   %-48s => %s\n", m.Module + "@" + m.Version, m.ReplaceModule + "@" + m.ReplaceVersion)
 
-	// NOTE This is what basically makes us BFS
-	for _, R := range m.SelfDeps {
-		err := mdr.VendorDep(R)
-		if err != nil {
-			mdr.errors = append(mdr.errors, err)
-		}
-	}
-
-	return nil
+	// NOTE the caller loads its dependencies next, which is what makes us BFS
+	return true
 }
 
 // TODO, break this function appart
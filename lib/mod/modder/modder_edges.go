@@ -0,0 +1,38 @@
+package modder
+
+// Edge is a require from one module version to another,
+// versions which lost to a greater one are kept too
+type Edge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Replace string `json:"replace,omitempty"`
+}
+
+func modVer(path, ver string) string {
+	if ver == "" {
+		return path
+	}
+	return path + "@" + ver
+}
+
+// addEdge records the require R from module m
+func (mdr *Modder) addEdge(m *Module, R Replace) {
+	E := Edge{
+		From: modVer(m.Module, m.Version),
+		To:   modVer(R.NewPath, R.NewVersion),
+	}
+	if R.OldPath != "" {
+		E.To = modVer(R.OldPath, R.OldVersion)
+		E.Replace = modVer(R.NewPath, R.NewVersion)
+	}
+	mdr.edges = append(mdr.edges, E)
+}
+
+// selected are the module versions chosen by MVS
+func (mdr *Modder) selected() map[string]bool {
+	sel := map[string]bool{}
+	for _, m := range mdr.depsMap {
+		sel[modVer(m.Module, m.Version)] = true
+	}
+	return sel
+}
@@ -0,0 +1,136 @@
+package modder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/hofstadter-io/hof/lib/yagu"
+)
+
+func (mdr *Modder) Get(queries []string) error {
+
+	// Get Command Override, the queries are appended
+	if len(mdr.CommandGet) > 0 {
+		for _, cmd := range mdr.CommandGet {
+			out, err := yagu.Exec(append(cmd, queries...))
+			fmt.Println(out)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// Otherwise, MVS venodiring
+		err := mdr.GetMVS(queries)
+		if err != nil {
+			mdr.PrintErrors()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The entrypoint to the MVS internal get process
+//
+// Each query is module@version, where version is a QueryVersion query or 'none'
+// to drop the require, a bare module is @upgrade. The requires are updated in the
+// mod file, then the dependencies are vendored, which selects versions with MVS
+// and updates the sum file. A greater version than asked for may be selected,
+// when another module requires it.
+func (mdr *Modder) GetMVS(queries []string) error {
+
+	// Load minimal root module
+	err := mdr.LoadMetaFromFS(".")
+	if err != nil {
+		return err
+	}
+
+	mf := mdr.module.ModFile
+
+	var wanted []Require
+	for _, q := range queries {
+		mod, query := q, "upgrade"
+		if i := strings.LastIndex(q, "@"); i >= 0 {
+			mod, query = q[:i], q[i+1:]
+		}
+		if mod == "" || query == "" {
+			return fmt.Errorf("Invalid query %q, should be module@version", q)
+		}
+		if mod == mdr.module.Module {
+			return fmt.Errorf("Can not get %q, it is the main module", q)
+		}
+
+		current := ""
+		for _, req := range mf.Require {
+			if req.Mod.Path == mod {
+				current = req.Mod.Version
+			}
+		}
+
+		if query == "none" {
+			if current == "" {
+				fmt.Printf("%s is not required\n", mod)
+				continue
+			}
+			fmt.Printf("removing %s %s\n", mod, current)
+			err = mf.DropRequire(mod)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		ver, err := mdr.QueryVersion(mod, query, current)
+		if err != nil {
+			return fmt.Errorf("While resolving %s\n%w\n", q, err)
+		}
+		wanted = append(wanted, Require{Path: mod, Version: ver})
+
+		switch c := semver.Compare(ver, current); {
+		case current == "":
+			fmt.Printf("adding %s %s\n", mod, ver)
+		case c > 0:
+			fmt.Printf("upgrading %s %s => %s\n", mod, current, ver)
+		case c < 0:
+			fmt.Printf("downgrading %s %s => %s\n", mod, current, ver)
+		}
+
+		if rep, ok := mdr.module.SelfDeps[mod]; ok && rep.OldPath != "" {
+			fmt.Printf("note: %s is replaced by %s\n", mod, modVer(rep.NewPath, rep.NewVersion))
+		}
+
+		err = mf.AddRequire(mod, ver)
+		if err != nil {
+			return err
+		}
+	}
+
+	mf.Cleanup()
+	mf.SortBlocks()
+	out, err := mf.Format()
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(mdr.ModFile, out, 0644)
+	if err != nil {
+		return err
+	}
+
+	// Resolve and vendor from the new mod file
+	err = mdr.VendorMVS()
+	if err != nil {
+		return err
+	}
+
+	for _, W := range wanted {
+		m, ok := mdr.depsMap[W.Path]
+		if ok && m.ReplaceModule == m.Module && m.Version != W.Version {
+			fmt.Printf("note: %s %s is selected, another module requires it\n", m.Module, m.Version)
+		}
+	}
+
+	return nil
+}
@@ -1,12 +1,14 @@
 package modder
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/hofstadter-io/hof/lib/yagu"
 )
 
-func (mdr *Modder) Graph() error {
+func (mdr *Modder) Graph(format string) error {
 
 	// Graph Command Override
 	if len(mdr.CommandGraph) > 0 {
@@ -19,7 +21,7 @@ func (mdr *Modder) Graph() error {
 		}
 	} else {
 		// Otherwise, MVS venodiring
-		err := mdr.GraphMVS()
+		err := mdr.GraphMVS(format)
 		if err != nil {
 			mdr.PrintErrors()
 			return err
@@ -29,8 +31,8 @@ func (mdr *Modder) Graph() error {
 	return nil
 }
 
-// The entrypoint to the MVS internal verify process
-func (mdr *Modder) GraphMVS() error {
+// The entrypoint to the MVS internal graph process
+func (mdr *Modder) GraphMVS(format string) error {
 
 	// Load minimal root module
 	err := mdr.LoadMetaFromFS(".")
@@ -38,5 +40,108 @@ func (mdr *Modder) GraphMVS() error {
 		return err
 	}
 
+	// Resolve the full graph, as vendoring would
+	err = mdr.LoadDeps()
+	if err != nil {
+		return err
+	}
+
+	edges := mdr.sortedEdges()
+
+	switch format {
+	case "", "text":
+		mdr.printGraphText(edges)
+	case "dot":
+		mdr.printGraphDot(edges)
+	case "json":
+		return mdr.printGraphJSON(edges)
+	default:
+		return fmt.Errorf("Unknown graph format %q, should be one of text, dot, json", format)
+	}
+
+	return nil
+}
+
+func (mdr *Modder) sortedEdges() []Edge {
+	edges := make([]Edge, len(mdr.edges))
+	copy(edges, mdr.edges)
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// Like 'go mod graph', one 'module@version dep@version' per line
+func (mdr *Modder) printGraphText(edges []Edge) {
+	for _, E := range edges {
+		if E.Replace != "" {
+			fmt.Printf("%s %s => %s\n", E.From, E.To, E.Replace)
+		} else {
+			fmt.Printf("%s %s\n", E.From, E.To)
+		}
+	}
+}
+
+// Selected versions are bold, the versions MVS passed over are gray
+func (mdr *Modder) printGraphDot(edges []Edge) {
+	sel := mdr.selected()
+
+	nodes := map[string]bool{}
+	for _, E := range edges {
+		nodes[E.From] = true
+		nodes[E.To] = true
+	}
+	names := make([]string, 0, len(nodes))
+	for n, _ := range nodes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("digraph %q {\n", mdr.Name)
+	for _, n := range names {
+		switch {
+		case n == mdr.module.Module:
+			fmt.Printf("  %q [shape=box];\n", n)
+		case sel[n]:
+			fmt.Printf("  %q [style=bold];\n", n)
+		default:
+			fmt.Printf("  %q [color=gray, fontcolor=gray];\n", n)
+		}
+	}
+	for _, E := range edges {
+		if E.Replace != "" {
+			fmt.Printf("  %q -> %q [label=%q];\n", E.From, E.To, "=> "+E.Replace)
+		} else {
+			fmt.Printf("  %q -> %q;\n", E.From, E.To)
+		}
+	}
+	fmt.Println("}")
+}
+
+type jsonGraph struct {
+	Root     string   `json:"root"`
+	Selected []string `json:"selected"`
+	Edges    []Edge   `json:"edges"`
+}
+
+func (mdr *Modder) printGraphJSON(edges []Edge) error {
+	G := jsonGraph{
+		Root:     mdr.module.Module,
+		Selected: []string{},
+		Edges:    edges,
+	}
+	for n, _ := range mdr.selected() {
+		G.Selected = append(G.Selected, n)
+	}
+	sort.Strings(G.Selected)
+
+	bytes, err := json.MarshalIndent(G, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bytes))
 	return nil
 }
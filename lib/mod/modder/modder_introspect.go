@@ -0,0 +1,134 @@
+package modder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hofstadter-io/hof/lib/yagu"
+)
+
+var quotedRE = regexp.MustCompile(`"([^"]+)"`)
+
+// Introspect scans the root module's source files for imports.
+// Files are selected with the Introspect globs, matched like the vendor globs,
+// against the path from the module root with a leading '/'.
+// The first capture group of each regex match holds the import, when it has
+// quoted strings, each of them is an import, so a regex can capture a whole import block.
+// Nested modules, those with their own mod file, and the mods dir are skipped.
+func (mdr *Modder) Introspect() ([]string, error) {
+	if len(mdr.IntrospectIncludeGlobs) == 0 || len(mdr.IntrospectExtractRegex) == 0 {
+		return nil, fmt.Errorf("Modder %s has no IntrospectIncludeGlobs or IntrospectExtractRegex, imports can not be discovered", mdr.Name)
+	}
+
+	var regexs []*regexp.Regexp
+	for _, r := range mdr.IntrospectExtractRegex {
+		re, err := regexp.Compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("Bad IntrospectExtractRegex %q for %s\n%w\n", r, mdr.Name, err)
+		}
+		if re.NumSubexp() < 1 {
+			return nil, fmt.Errorf("IntrospectExtractRegex %q for %s needs a capture group", r, mdr.Name)
+		}
+		regexs = append(regexs, re)
+	}
+
+	found := map[string]bool{}
+
+	err := filepath.Walk(".", func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		fpath = filepath.ToSlash(fpath)
+
+		if info.IsDir() {
+			if fpath == "." {
+				return nil
+			}
+			if fpath == path.Clean(mdr.ModsDir) || info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(fpath, mdr.ModFile)); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		include, err := yagu.CheckShouldInclude("/"+fpath, mdr.IntrospectIncludeGlobs, mdr.IntrospectExcludeGlobs)
+		if err != nil || !include {
+			return err
+		}
+
+		content, err := ioutil.ReadFile(fpath)
+		if err != nil {
+			return err
+		}
+
+		for _, re := range regexs {
+			for _, match := range re.FindAllSubmatch(content, -1) {
+				group := string(match[1])
+				quoted := quotedRE.FindAllStringSubmatch(group, -1)
+				if len(quoted) == 0 {
+					found[strings.TrimSpace(group)] = true
+					continue
+				}
+				for _, q := range quoted {
+					found[q[1]] = true
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imports := make([]string, 0, len(found))
+	for imp, _ := range found {
+		if imp != "" {
+			imports = append(imports, imp)
+		}
+	}
+	sort.Strings(imports)
+
+	return imports, nil
+}
+
+// importModule finds the module an import belongs to, from those known,
+// otherwise it is the <remote>/<owner>/<repo> prefix. Builtin packages,
+// which have no domain, and the root module's own packages return "".
+func (mdr *Modder) importModule(imp string, known []string) string {
+	if !strings.Contains(strings.Split(imp, "/")[0], ".") {
+		return ""
+	}
+	if inModule(imp, mdr.module.Module) {
+		return ""
+	}
+
+	// longest match wins, for modules nested in another's path
+	best := ""
+	for _, mod := range known {
+		if inModule(imp, mod) && len(mod) > len(best) {
+			best = mod
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	flds := strings.Split(imp, "/")
+	if len(flds) < 3 {
+		return imp
+	}
+	return strings.Join(flds[:3], "/")
+}
+
+func inModule(imp, mod string) bool {
+	return imp == mod || strings.HasPrefix(imp, mod+"/")
+}
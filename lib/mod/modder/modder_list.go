@@ -0,0 +1,94 @@
+package modder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/hofstadter-io/hof/lib/mod/cache"
+	"github.com/hofstadter-io/hof/lib/yagu"
+)
+
+func (mdr *Modder) List(upgrades bool) error {
+
+	// List Command Override
+	if len(mdr.CommandList) > 0 {
+		for _, cmd := range mdr.CommandList {
+			out, err := yagu.Exec(cmd)
+			fmt.Println(out)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// Otherwise, MVS venodiring
+		err := mdr.ListMVS(upgrades)
+		if err != nil {
+			mdr.PrintErrors()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The entrypoint to the MVS internal list process
+//
+// Like 'go list -m all', the main module and then the selected versions,
+// one 'module version [=> replacement version]' per line. With upgrades,
+// the latest version is appended in brackets when it is greater.
+// Locally replaced modules are not checked.
+func (mdr *Modder) ListMVS(upgrades bool) error {
+
+	// Load minimal root module
+	err := mdr.LoadMetaFromFS(".")
+	if err != nil {
+		return err
+	}
+
+	err = mdr.LoadDeps()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(mdr.module.Module)
+
+	var mods []string
+	for mod, _ := range mdr.depsMap {
+		mods = append(mods, mod)
+	}
+	sort.Strings(mods)
+
+	for _, mod := range mods {
+		m := mdr.depsMap[mod]
+
+		line := mod
+		if m.Version != "" {
+			line += " " + m.Version
+		}
+
+		if m.ReplaceModule != m.Module {
+			line += " => " + m.ReplaceModule
+			if m.ReplaceVersion != "" {
+				line += " " + m.ReplaceVersion
+			}
+		}
+
+		if upgrades && !strings.HasPrefix(m.ReplaceModule, ".") {
+			vers, err := mdr.Versions(mod)
+			if err != nil {
+				return err
+			}
+			latest := cache.Latest(vers)
+			if latest != "" && semver.Compare(latest, m.Version) > 0 {
+				line += " [" + latest + "]"
+			}
+		}
+
+		fmt.Println(line)
+	}
+
+	return nil
+}
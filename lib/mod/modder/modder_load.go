@@ -4,6 +4,9 @@ import (
 	"fmt"
 
 	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-billy/v5/util"
+
+	"github.com/hofstadter-io/hof/lib/gotils/par"
 )
 
 /* Reads the module files relative to the supplied dir from local FS
@@ -85,6 +88,8 @@ func (mdr *Modder) LoadMetaFromFS(dir string) error {
 		FS: mdr.FS,
 	}
 	mdr.depsMap = map[string]*Module{}
+	mdr.edges = nil
+	mdr.loads = new(par.Cache)
 
 	// Load module files
 	var err error
@@ -113,7 +118,9 @@ func (mdr *Modder) LoadModFile() error {
 	fn := mdr.ModFile
 	m := mdr.module
 
-	err := m.LoadModFile(fn, false /* Do load replace directives! */)
+	// branches, commits, and the default branch are resolved to versions
+	var resolved []string
+	err := m.LoadModFile(fn, false /* Do load replace directives! */, mdr.versionFixer(&resolved))
 	if err != nil {
 		return err
 	}
@@ -125,6 +132,21 @@ func (mdr *Modder) LoadModFile() error {
 
 	m.Module = m.ModFile.Module.Mod.Path
 
+	// so they are recorded
+	if len(resolved) > 0 {
+		for _, r := range resolved {
+			fmt.Println(r)
+		}
+		out, err := m.ModFile.Format()
+		if err != nil {
+			return err
+		}
+		err = util.WriteFile(m.FS, fn, out, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
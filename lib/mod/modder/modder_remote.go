@@ -0,0 +1,161 @@
+package modder
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hofstadter-io/hof/lib/mod/cache"
+)
+
+// ModProxyEnv overrides the Proxy setting of every modder
+const ModProxyEnv = "HOF_MOD_PROXY"
+
+// Offline only uses the module cache, fetching is an error, like a proxy of 'off'
+var Offline bool
+
+// ModProxy is a comma separated list of module proxies, tried in order like GOPROXY.
+// The next is only tried when a proxy does not have the module, so a failing proxy
+// is an error rather than a silent change of source. 'direct' fetches from the git
+// remote and 'off' stops, so ending the list with 'off' only allows the proxies.
+// The default is direct, and Offline is always 'off'.
+func (mdr *Modder) ModProxy() []string {
+	if Offline {
+		return []string{"off"}
+	}
+
+	P := os.Getenv(ModProxyEnv)
+	if P == "" {
+		P = mdr.Proxy
+	}
+	if P == "" {
+		P = "direct"
+	}
+
+	var proxies []string
+	for _, p := range strings.Split(P, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
+
+// RemoteURL is the git remote for a module, from the longest
+// matching prefix in Remotes, or the https default.
+// Prefixes match whole path elements, example.com/a does not match example.com/ab
+func (mdr *Modder) RemoteURL(mod string) string {
+	best := ""
+	for prefix, _ := range mdr.Remotes {
+		if matchPrefix(mod, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return cache.RemoteURL(mod)
+	}
+
+	return mdr.Remotes[best] + strings.TrimPrefix(mod, best)
+}
+
+// matchPrefix is true when prefix is mod, or a leading path of it
+func matchPrefix(mod, prefix string) bool {
+	if mod == prefix {
+		return true
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return strings.HasPrefix(mod, prefix)
+}
+
+// fetchModule puts mod at ver into the cache, from the first proxy which has it
+func (mdr *Modder) fetchModule(mod, ver string) error {
+	ok, err := cache.Has(mdr.Name, mod, ver)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+
+	// another hof may be fetching it, the lock waits for it to finish
+	unlock, err := cache.Lock(mdr.Name, mod, ver)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ok, err = cache.Has(mdr.Name, mod, ver)
+	if err != nil || ok {
+		return err
+	}
+
+	// err is the last not found, if any
+	for _, P := range mdr.ModProxy() {
+		switch P {
+		case "off":
+			return offError(mod+"@"+ver+" is not in the cache", err)
+		case "direct":
+			err = cache.Fetch(mdr.Name, mod, ver, mdr.RemoteURL(mod))
+		default:
+			err = cache.FetchProxy(mdr.Name, mod, ver, P)
+		}
+		if !errors.Is(err, cache.ErrNotFound) {
+			return err
+		}
+	}
+
+	if err == nil {
+		err = fmt.Errorf("No module proxies for %s", mdr.Name)
+	}
+	return err
+}
+
+// Versions lists the semver versions of a module, lowest first, from the first proxy which has it
+func (mdr *Modder) Versions(mod string) ([]string, error) {
+	var vers []string
+	var err error
+	for _, P := range mdr.ModProxy() {
+		switch P {
+		case "off":
+			return nil, offError("Can not list the versions of "+mod, err)
+		case "direct":
+			vers, err = cache.RemoteVersions(mdr.RemoteURL(mod))
+		default:
+			vers, err = cache.ProxyVersions(mod, P)
+		}
+		if !errors.Is(err, cache.ErrNotFound) {
+			return vers, err
+		}
+	}
+
+	if err == nil {
+		err = fmt.Errorf("No module proxies for %s", mdr.Name)
+	}
+	return nil, err
+}
+
+func offError(msg string, notFound error) error {
+	if Offline {
+		return fmt.Errorf("%s, and fetching is disabled by --offline", msg)
+	}
+	if notFound != nil {
+		return fmt.Errorf("%s, and fetching stops at 'off'\n%w\n", msg, notFound)
+	}
+	return fmt.Errorf("%s, and fetching is 'off'", msg)
+}
+
+// LatestVersion is the greatest release of a module, or prerelease when there are no releases,
+// and a pseudo-version for HEAD of the default branch when there are no tags at all
+func (mdr *Modder) LatestVersion(mod string) (string, error) {
+	vers, err := mdr.Versions(mod)
+	if err != nil {
+		return "", err
+	}
+	if len(vers) == 0 {
+		return mdr.ResolveQuery(mod, "HEAD")
+	}
+	return cache.Latest(vers), nil
+}
@@ -1 +1,119 @@
 package modder
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/hofstadter-io/hof/lib/mod/cache"
+	"github.com/hofstadter-io/hof/lib/mod/parse/modfile"
+)
+
+// ResolveQuery turns a branch, commit, or HEAD for the default branch, into a version,
+// the tag when the commit has one, otherwise a pseudo-version, from the first proxy which has it
+func (mdr *Modder) ResolveQuery(mod, query string) (string, error) {
+	var ver string
+	var err error
+	for _, P := range mdr.ModProxy() {
+		switch P {
+		case "off":
+			return "", offError("Can not resolve "+mod+"@"+query, err)
+		case "direct":
+			ver, err = cache.ResolveVersion(mdr.RemoteURL(mod), query)
+		default:
+			ver, err = cache.ProxyInfo(mod, query, P)
+		}
+		if !errors.Is(err, cache.ErrNotFound) {
+			return ver, err
+		}
+	}
+
+	if err == nil {
+		err = fmt.Errorf("No module proxies for %s", mdr.Name)
+	}
+	return "", err
+}
+
+// versionFixer resolves what is not semver when mod files are parsed,
+// and appends a message for each to resolved, when not nil
+func (mdr *Modder) versionFixer(resolved *[]string) modfile.VersionFixer {
+	return func(path, vers string) (string, error) {
+		if semver.IsValid(vers) {
+			return vers, nil
+		}
+
+		ver, err := mdr.ResolveQuery(path, vers)
+		if err != nil {
+			return "", err
+		}
+
+		if resolved != nil {
+			query := vers
+			if query == "HEAD" {
+				query = "v0.0.0"
+			}
+			*resolved = append(*resolved, fmt.Sprintf("resolved %s@%s => %s", path, query, ver))
+		}
+		return ver, nil
+	}
+}
+
+// QueryVersion resolves a query for mod, current is the required version or "".
+//
+//   latest   the greatest release, or HEAD of the default branch when untagged
+//   upgrade  latest, unless current is already greater
+//   patch    the greatest release with the same major and minor as current
+//   v1.2.3   the version, which must exist
+//   <other>  a branch or commit, see ResolveQuery
+func (mdr *Modder) QueryVersion(mod, query, current string) (string, error) {
+	switch query {
+	case "latest":
+		return mdr.LatestVersion(mod)
+
+	case "upgrade":
+		latest, err := mdr.LatestVersion(mod)
+		if err != nil {
+			return "", err
+		}
+		if current != "" && semver.Compare(current, latest) > 0 {
+			return current, nil
+		}
+		return latest, nil
+
+	case "patch":
+		if current == "" {
+			return mdr.LatestVersion(mod)
+		}
+		vers, err := mdr.Versions(mod)
+		if err != nil {
+			return "", err
+		}
+		best := current
+		for _, v := range vers {
+			if semver.MajorMinor(v) == semver.MajorMinor(current) && semver.Prerelease(v) == "" && semver.Compare(v, best) > 0 {
+				best = v
+			}
+		}
+		return best, nil
+	}
+
+	if !semver.IsValid(query) {
+		return mdr.ResolveQuery(mod, query)
+	}
+
+	ver := semver.Canonical(query)
+	if cache.IsPseudoVersion(ver) {
+		return ver, nil
+	}
+	vers, err := mdr.Versions(mod)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range vers {
+		if v == ver {
+			return ver, nil
+		}
+	}
+	return "", fmt.Errorf("Unknown version %s@%s", mod, query)
+}
@@ -2,7 +2,10 @@ package modder
 
 import (
 	"fmt"
+	"io/ioutil"
+	"sort"
 
+	"github.com/hofstadter-io/hof/lib/mod/parse/sumfile"
 	"github.com/hofstadter-io/hof/lib/yagu"
 )
 
@@ -29,7 +32,13 @@ func (mdr *Modder) Tidy() error {
 	return nil
 }
 
-// The entrypoint to the MVS internal verify process
+// The entrypoint to the MVS internal tidy process
+//
+// Imports are discovered by introspecting the root module's files.
+// Missing modules are required at their latest version, and requires
+// which are no longer imported are dropped. Indirect requires are kept,
+// as they pin the versions of transitive dependencies. Replaces are left as is.
+// The dependencies are then resolved and the sum file is rewritten from scratch.
 func (mdr *Modder) TidyMVS() error {
 
 	// Load minimal root module
@@ -38,5 +47,89 @@ func (mdr *Modder) TidyMVS() error {
 		return err
 	}
 
-	return nil
+	imports, err := mdr.Introspect()
+	if err != nil {
+		return err
+	}
+
+	mf := mdr.module.ModFile
+
+	var known []string
+	for path, _ := range mdr.module.SelfDeps {
+		known = append(known, path)
+	}
+
+	needed := map[string]bool{}
+	for _, imp := range imports {
+		if mod := mdr.importModule(imp, known); mod != "" {
+			needed[mod] = true
+		}
+	}
+
+	required := map[string]bool{}
+	for _, req := range mf.Require {
+		required[req.Mod.Path] = true
+		if needed[req.Mod.Path] || req.Indirect {
+			continue
+		}
+		fmt.Printf("removing %s %s\n", req.Mod.Path, req.Mod.Version)
+		err = mf.DropRequire(req.Mod.Path)
+		if err != nil {
+			return err
+		}
+	}
+
+	// modules only in a replace are already provided
+	var missing []string
+	for mod, _ := range needed {
+		if _, ok := mdr.module.SelfDeps[mod]; !ok && !required[mod] {
+			missing = append(missing, mod)
+		}
+	}
+	sort.Strings(missing)
+
+	for _, mod := range missing {
+		ver, err := mdr.LatestVersion(mod)
+		if err != nil {
+			return fmt.Errorf("While finding the latest version of %s, which is imported but not required\n%w\n", mod, err)
+		}
+		fmt.Printf("adding %s %s\n", mod, ver)
+		mf.AddNewRequire(mod, ver, false)
+	}
+
+	mf.Cleanup()
+	mf.SortBlocks()
+	out, err := mf.Format()
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(mdr.ModFile, out, 0644)
+	if err != nil {
+		return err
+	}
+
+	// Resolve from the new mod file, so the sums match
+	err = mdr.LoadMetaFromFS(".")
+	if err != nil {
+		return err
+	}
+
+	err = mdr.LoadDeps()
+	if err != nil {
+		return err
+	}
+
+	// Only the selected versions, stale sums are dropped
+	mdr.module.SumFile = &sumfile.Sum{}
+	for _, m := range mdr.depsMap {
+		if m.Version == "" {
+			m.Version = "v0.0.0"
+		}
+		err = mdr.addSums(m)
+		if err != nil {
+			return err
+		}
+	}
+
+	return mdr.writeSumFile()
 }
@@ -75,14 +75,9 @@ func (mdr *Modder) VendorMVS() error {
 		// fmt.Println(err)
 		return err
 	}
-	for _, R := range mdr.module.SelfDeps {
-		err := mdr.VendorDep(R)
-		if err != nil {
-			mdr.errors = append(mdr.errors, err)
-		}
-	}
 
-	if err := mdr.CheckForErrors(); err != nil {
+	err = mdr.LoadDeps()
+	if err != nil {
 		return err
 	}
 
@@ -94,49 +89,20 @@ func (mdr *Modder) VendorMVS() error {
 	return nil
 }
 
-func (mdr *Modder) VendorDep(R Replace) error {
+// VendorDep fetches and loads the module for a require, it is merged by the caller
+func (mdr *Modder) VendorDep(R Replace) (*Module, error) {
 	// fmt.Printf("VendorDep %#+v\n", R)
 
 	// Fetch and Load module
 	if strings.HasPrefix(R.NewPath, "./") || strings.HasPrefix(R.NewPath, "../") {
-		err := mdr.LoadLocalReplace(R)
-		if err != nil {
-			mdr.errors = append(mdr.errors, err)
-			return err
-		}
-		return nil
-	} else {
-		err := mdr.LoadRemoteModule(R)
-		if err != nil {
-			mdr.errors = append(mdr.errors, err)
-			return err
-		}
-		return nil
+		return mdr.LoadLocalReplace(R)
 	}
-
-	return nil
+	return mdr.LoadRemoteModule(R)
 }
 
-func (mdr *Modder) LoadRemoteModule(R Replace) error {
+func (mdr *Modder) LoadRemoteModule(R Replace) (*Module, error) {
 	// fmt.Printf("LoadRemoteReplace %#+v\n", R)
 
-	// If sumfile, check integrity and possibly shortcut
-	if mdr.module.SumFile != nil {
-		mdr.CompareSumEntryToVendor(R)
-		/*
-		err := mdr.CompareSumEntryToVendor(R)
-		if err == nil {
-			// return nil
-			// TODO, add dependencies to processing
-		} else {
-			fmt.Println("Error:", err)
-		}
-		*/
-	}
-
-	// TODO, check if valid and just add m.deps to processing
-	// We can do this in a BFS manner
-
 	m := &Module{
 		Module:         R.OldPath,
 		Version:        R.OldVersion,
@@ -149,34 +115,31 @@ func (mdr *Modder) LoadRemoteModule(R Replace) error {
 		m.Version = R.NewVersion
 	}
 
-	err := cache.Fetch(mdr.Name, R.NewPath, R.NewVersion)
+	err := mdr.fetchModule(R.NewPath, R.NewVersion)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	m.FS, err = cache.Load(mdr.Name, R.NewPath, R.NewVersion)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = m.LoadMetaFiles(mdr.ModFile, mdr.SumFile, mdr.MappingFile, true /* ignoreReplace directives */)
+	// before anything is read from it
+	err = mdr.checkSums(m)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// TODO, check if valid and just add m.deps to processing
-
-	// XXX Within this call is what basically makes us BFS
-	// It's where we load the dependencies
-	err = mdr.MvsMergeDependency(m)
+	err = m.LoadMetaFiles(mdr.ModFile, mdr.SumFile, mdr.MappingFile, true /* ignoreReplace directives */, mdr.versionFixer(nil))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return m, nil
 }
 
-func (mdr *Modder) LoadLocalReplace(R Replace) error {
+func (mdr *Modder) LoadLocalReplace(R Replace) (*Module, error) {
 	// fmt.Printf("LoadLocalReplace %#+v\n", R)
 	var err error
 
@@ -189,19 +152,10 @@ func (mdr *Modder) LoadLocalReplace(R Replace) error {
 
 	m.FS = osfs.New(R.NewPath)
 
-	err = m.LoadMetaFiles(mdr.ModFile, mdr.SumFile, mdr.MappingFile, true /* ignoreReplace directives */)
+	err = m.LoadMetaFiles(mdr.ModFile, mdr.SumFile, mdr.MappingFile, true /* ignoreReplace directives */, mdr.versionFixer(nil))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// TODO, check if valid and just add m.deps to processing
-
-	// XXX Within this call is what basically makes us BFS
-	// It's where we load the dependencies
-	err = mdr.MvsMergeDependency(m)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return m, nil
 }
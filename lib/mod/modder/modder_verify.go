@@ -2,6 +2,8 @@ package modder
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/hofstadter-io/hof/lib/yagu"
 )
@@ -30,44 +32,58 @@ func (mdr *Modder) Verify() error {
 }
 
 // The entrypoint to the MVS internal verify process
+//
+// Dependencies are loaded as for vendoring, which checks the fetched and cached
+// modules against the sum file. Then every selected module must have sums, and its
+// vendored files must be unchanged copies. Local replaces are compared to the vendored copy.
 func (mdr *Modder) VerifyMVS() error {
 
-	valid := true
-
 	// Load minimal root module
 	err := mdr.LoadMetaFromFS(".")
 	if err != nil {
 		return err
 	}
 
-	// Load the root module's deps
-	present, missing, local, err := mdr.PartitionSumEntries()
+	sf := mdr.module.SumFile
+	if sf == nil {
+		return fmt.Errorf("No sum file %q for %s, run 'hof mod vendor %s' to create it", mdr.SumFile, mdr.Name, mdr.Name)
+	}
+
+	err = mdr.LoadDeps()
 	if err != nil {
 		return err
 	}
 
-	// Invalid if there are missing deps
-	for _, m := range missing {
-		valid = false
-		R := mdr.module.SelfDeps[m]
-		err := fmt.Errorf("Sumfile missing: %s@%s", R.NewPath, R.NewVersion)
-		mdr.errors = append(mdr.errors, err)
+	var mods []string
+	for mod, _ := range mdr.depsMap {
+		mods = append(mods, mod)
 	}
+	sort.Strings(mods)
 
-	for _, p := range present {
-		R := mdr.module.SelfDeps[p]
-		err := mdr.CompareSumEntryToVendor(R)
-		// Something is wrong with the vendored copy or the hash
-		if err != nil {
+	valid := true
+	for _, mod := range mods {
+		m := mdr.depsMap[mod]
+
+		if strings.HasPrefix(m.ReplaceModule, ".") {
+			R := Replace{OldPath: m.Module, NewPath: m.ReplaceModule}
+			err := mdr.CompareLocalReplaceToVendor(R)
+			// Something is wrong with the vendored copy
+			if err != nil {
+				valid = false
+			}
+			continue
+		}
+
+		dver, _ := mdr.sumVersions(m)
+		if _, ok := sf.Mods[dver]; !ok {
 			valid = false
+			err := fmt.Errorf("Sumfile missing: %s@%s", m.Module, m.Version)
 			mdr.errors = append(mdr.errors, err)
+			continue
 		}
-	}
 
-	for _, p := range local {
-		R := mdr.module.SelfDeps[p]
-		err := mdr.CompareLocalReplaceToVendor(R)
-		// Something is wrong with the vendored copy or the hash
+		err := mdr.CompareModuleToVendor(m)
+		// Something is wrong with the vendored copy
 		if err != nil {
 			valid = false
 			mdr.errors = append(mdr.errors, err)
@@ -75,9 +91,10 @@ func (mdr *Modder) VerifyMVS() error {
 	}
 
 	if !valid {
-		return fmt.Errorf("Vendoring is in an inconsistent state, please run 'mvs vendor %s' ", mdr.Name)
+		return fmt.Errorf("Vendoring is in an inconsistent state, please run 'hof mod vendor %s'", mdr.Name)
 	}
 
 	// We are OK!
+	fmt.Println("all modules verified")
 	return nil
 }
@@ -0,0 +1,164 @@
+package modder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hofstadter-io/hof/lib/yagu"
+)
+
+func (mdr *Modder) Why(targets []string, modules bool) error {
+
+	// Why Command Override, -m and the targets are appended
+	if len(mdr.CommandWhy) > 0 {
+		args := targets
+		if modules {
+			args = append([]string{"-m"}, targets...)
+		}
+		for _, cmd := range mdr.CommandWhy {
+			out, err := yagu.Exec(append(cmd, args...))
+			fmt.Println(out)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// Otherwise, MVS venodiring
+		err := mdr.WhyMVS(targets, modules)
+		if err != nil {
+			mdr.PrintErrors()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The entrypoint to the MVS internal why process
+//
+// Like 'go mod why', each target is printed as '# target', followed by the shortest
+// chain of requires from the root module to it, through the selected versions.
+// Targets are package import paths, which belong to the selected module with
+// the longest matching path, or module paths when modules is set.
+func (mdr *Modder) WhyMVS(targets []string, modules bool) error {
+
+	// Load minimal root module
+	err := mdr.LoadMetaFromFS(".")
+	if err != nil {
+		return err
+	}
+
+	// Resolve the full graph, as vendoring would
+	err = mdr.LoadDeps()
+	if err != nil {
+		return err
+	}
+
+	paths := mdr.shortestPaths()
+
+	for i, target := range targets {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println("#", target)
+
+		mod, kind := target, "module"
+		if !modules {
+			mod, kind = mdr.packageModule(target), "package"
+		}
+
+		chain, ok := paths[mod]
+		if !ok {
+			fmt.Printf("(main module does not need %s %s)\n", kind, target)
+			continue
+		}
+
+		for _, c := range chain {
+			fmt.Println(mdr.whyLine(c))
+		}
+		if !modules && target != mod {
+			fmt.Println(target)
+		}
+	}
+
+	return nil
+}
+
+// shortestPaths does a breadth first search of the requires from the root module,
+// following only the selected versions. The result is the chain of module paths
+// to each module reached, with the first found among chains of the same length.
+func (mdr *Modder) shortestPaths() map[string][]string {
+	sel := mdr.selected()
+	root := mdr.module.Module
+
+	// requires of the root and the selected versions, by module path
+	next := map[string][]string{}
+	for _, E := range mdr.sortedEdges() {
+		if E.From != modVer(root, mdr.module.Version) && !sel[E.From] {
+			continue
+		}
+		from, to := modPath(E.From), modPath(E.To)
+		next[from] = append(next[from], to)
+	}
+
+	paths := map[string][]string{root: []string{root}}
+	queue := []string{root}
+	for len(queue) > 0 {
+		mod := queue[0]
+		queue = queue[1:]
+		for _, dep := range next[mod] {
+			if _, ok := paths[dep]; ok {
+				continue
+			}
+			chain := make([]string, len(paths[mod]), len(paths[mod])+1)
+			copy(chain, paths[mod])
+			paths[dep] = append(chain, dep)
+			queue = append(queue, dep)
+		}
+	}
+
+	return paths
+}
+
+// packageModule is the module a package import path belongs to
+func (mdr *Modder) packageModule(pkg string) string {
+	mods := []string{mdr.module.Module}
+	for mod, _ := range mdr.depsMap {
+		mods = append(mods, mod)
+	}
+	sort.Strings(mods)
+
+	best := ""
+	for _, mod := range mods {
+		if (pkg == mod || strings.HasPrefix(pkg, mod+"/")) && len(mod) > len(best) {
+			best = mod
+		}
+	}
+	if best == "" {
+		return pkg
+	}
+	return best
+}
+
+// whyLine is a module of a chain, with its selected version and any replacement
+func (mdr *Modder) whyLine(mod string) string {
+	m, ok := mdr.depsMap[mod]
+	if !ok {
+		// the root module
+		return mod
+	}
+
+	line := modVer(m.Module, m.Version)
+	if m.ReplaceModule != m.Module {
+		line += " => " + modVer(m.ReplaceModule, m.ReplaceVersion)
+	}
+	return line
+}
+
+func modPath(mv string) string {
+	if i := strings.LastIndex(mv, "@"); i >= 0 {
+		return mv[:i]
+	}
+	return mv
+}
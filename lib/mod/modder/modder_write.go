@@ -7,6 +7,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/hofstadter-io/hof/lib/mod/cache"
 	"github.com/hofstadter-io/hof/lib/mod/parse/sumfile"
 	"github.com/hofstadter-io/hof/lib/yagu"
 )
@@ -46,33 +47,10 @@ func (mdr *Modder) WriteVendor() error {
 			m.Version = "v0.0.0"
 		}
 
-		dirhash, err := yagu.BillyCalcHash(m.FS)
+		err := mdr.addSums(m)
 		if err != nil {
-			mdr.errors = append(mdr.errors, err)
-			return fmt.Errorf("While calculating billy dir hash for %q\n%w\n", mdr.ModsDir, err)
-		}
-
-		modhash, err := yagu.BillyCalcFileHash(mdr.ModFile, m.FS)
-		if err != nil {
-			mdr.errors = append(mdr.errors, err)
-			return fmt.Errorf("While calculating billy mod hash\n%w\n", err)
-		}
-
-
-		dver := sumfile.Version{
-			Path: strings.Join([]string{m.Module}, "/"),
-			Version: m.Version,
-		}
-		if mdr.module.SumFile == nil {
-			mdr.module.SumFile = &sumfile.Sum{}
-		}
-		mdr.module.SumFile.Add(dver, dirhash)
-
-		mver := sumfile.Version{
-			Path: strings.Join([]string{m.Module}, "/"),
-			Version: strings.Join([]string{m.Version, mdr.ModFile}, "/"),
+			return err
 		}
-		mdr.module.SumFile.Add(mver, modhash)
 
 		baseDir := path.Join(mdr.ModsDir, m.Module)
 
@@ -122,13 +100,45 @@ func (mdr *Modder) WriteVendor() error {
 		mdr.module.SumFile = &sumfile.Sum{}
 	}
 
-	// Write sumfile
-	out, err := mdr.module.SumFile.Write()
+	return mdr.writeSumFile()
+}
+
+// addSums records the dir and mod file hashes of a dependency in the root sum file,
+// fetched modules were checked against any previous entry when loaded
+func (mdr *Modder) addSums(m *Module) error {
+	dirhash, err := cache.HashDir(m.FS)
 	if err != nil {
-		return err
+		mdr.errors = append(mdr.errors, err)
+		return fmt.Errorf("While calculating dir hash for %s@%s\n%w\n", m.Module, m.Version, err)
 	}
 
-	ioutil.WriteFile(mdr.SumFile, []byte(out), 0644)
+	modhash, err := cache.HashModFile(mdr.ModFile, m.FS)
+	if err != nil {
+		mdr.errors = append(mdr.errors, err)
+		return fmt.Errorf("While calculating mod hash for %s@%s\n%w\n", m.Module, m.Version, err)
+	}
+
+	if mdr.module.SumFile == nil {
+		mdr.module.SumFile = &sumfile.Sum{}
+	}
+	sf := mdr.module.SumFile
+	if sf.Mods == nil {
+		sf.Mods = map[sumfile.Version][]string{}
+	}
+
+	// replaced, so local replaces stay current
+	dver, mver := mdr.sumVersions(m)
+	sf.Mods[dver] = []string{dirhash}
+	sf.Mods[mver] = []string{modhash}
 
 	return nil
 }
+
+func (mdr *Modder) writeSumFile() error {
+	out, err := mdr.module.SumFile.Write()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(mdr.SumFile, []byte(out), 0644)
+}
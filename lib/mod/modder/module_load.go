@@ -10,7 +10,9 @@ import (
 	"github.com/hofstadter-io/hof/lib/yagu"
 )
 
-func (m *Module) LoadModFile(fn string, ignoreReplace bool) error {
+// LoadModFile parses the mod file, fix resolves versions which are not semver,
+// and v0.0.0 requires, which are the default branch, unless they are replaced
+func (m *Module) LoadModFile(fn string, ignoreReplace bool, fix modfile.VersionFixer) error {
 
 	modBytes, err := yagu.BillyReadAll(fn, m.FS)
 	if err != nil {
@@ -18,10 +20,33 @@ func (m *Module) LoadModFile(fn string, ignoreReplace bool) error {
 			return err
 		}
 	} else {
-		f, err := modfile.Parse(fn, modBytes, nil)
+		f, err := modfile.Parse(fn, modBytes, fix)
 		if err != nil {
 			return err
 		}
+
+		if fix != nil {
+			// duplicates are left for MergeSelfDeps to report
+			skip := map[string]int{}
+			for _, req := range f.Require {
+				skip[req.Mod.Path]++
+			}
+			if !ignoreReplace {
+				for _, rep := range f.Replace {
+					skip[rep.Old.Path]++
+				}
+			}
+			for _, req := range f.Require {
+				if req.Mod.Version != "v0.0.0" || skip[req.Mod.Path] > 1 {
+					continue
+				}
+				ver, err := fix(req.Mod.Path, "HEAD")
+				if err != nil {
+					return err
+				}
+				f.AddRequire(req.Mod.Path, ver)
+			}
+		}
 		m.ModFile = f
 		m.Language = f.Language.Name
 		m.LangVer = f.Language.Version
@@ -123,12 +148,12 @@ func (m *Module) LoadMappingFile(fn string) error {
 	return nil
 }
 
-func (m *Module) LoadMetaFiles(modname, sumname, mapname string, ignoreReplace bool) error {
+func (m *Module) LoadMetaFiles(modname, sumname, mapname string, ignoreReplace bool, fix modfile.VersionFixer) error {
 	var err error
 
 	// TODO load the modules .mvsconfig if present
 
-	err = m.LoadModFile(modname, ignoreReplace)
+	err = m.LoadModFile(modname, ignoreReplace, fix)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,404 @@
+package lib
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cuelang.org/go/cue/errors"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+// Report is the machine readable result of a gen run, for CI
+type Report struct {
+	Generators []*GeneratorReport `json:"generators"`
+	// Errors not specific to a generator, i.e. loading the Cue
+	Errors    []ReportError `json:"errors,omitempty"`
+	TotalTime time.Duration `json:"totalTime"`
+}
+
+type GeneratorReport struct {
+	Name    string              `json:"name"`
+	Failed  bool                `json:"failed"`
+	Stats   *gen.GeneratorStats `json:"stats"`
+	Errors  []ReportError       `json:"errors,omitempty"`
+	Files   []*FileReport       `json:"files"`
+	Deleted []string            `json:"deleted,omitempty"`
+}
+
+type FileReport struct {
+	Filepath string        `json:"filepath"`
+	Status   string        `json:"status"`
+	Stats    gen.FileStats `json:"stats"`
+	Errors   []ReportError `json:"errors,omitempty"`
+}
+
+// ReportError has the position when one is known, so CI can annotate the source
+type ReportError struct {
+	Message  string `json:"message"`
+	Filename string `json:"filename,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// text/template errors look like "template: name:3:12: ..."
+var templateErrorRE = regexp.MustCompile(`^template: ([^:]+):(\d+):(?:(\d+):)?`)
+
+func reportErrors(errs []error) []ReportError {
+	var RE []ReportError
+	for _, err := range errs {
+		// expand Cue error lists
+		for _, e := range errors.Errors(err) {
+			r := ReportError{Message: e.Error()}
+
+			if pos := errors.Positions(e); len(pos) > 0 {
+				r.Filename = pos[0].Filename()
+				r.Line = pos[0].Line()
+				r.Column = pos[0].Column()
+			} else if m := templateErrorRE.FindStringSubmatch(r.Message); m != nil {
+				r.Filename = m[1]
+				r.Line, _ = strconv.Atoi(m[2])
+				r.Column, _ = strconv.Atoi(m[3])
+			}
+
+			RE = append(RE, r)
+		}
+	}
+	return RE
+}
+
+// fileStatus picks the most important of the status flags
+func fileStatus(F *gen.File) string {
+	switch {
+	case F.IsErr > 0:
+		return "error"
+	case F.IsConflicted > 0:
+		return "conflict"
+	case F.IsSkipped > 0:
+		return "skipped"
+	case F.IsNew > 0:
+		return "new"
+	case F.IsModified > 0:
+		return "modified"
+	case F.IsSame > 0:
+		return "same"
+	}
+	// loading failed before the file was rendered
+	return "unrendered"
+}
+
+// BuildReport collects the results, errs are those not specific to a generator
+func (R *Runtime) BuildReport(errs []error, elapsed time.Duration) *Report {
+	report := &Report{
+		Generators: []*GeneratorReport{},
+		Errors:     reportErrors(errs),
+		TotalTime:  elapsed,
+	}
+
+	for _, G := range R.Ordered {
+		if G.Disabled {
+			continue
+		}
+
+		G.Stats.CalcTotals(G)
+		GR := &GeneratorReport{
+			Name:    G.Name,
+			Failed:  G.Failed,
+			Stats:   G.Stats,
+			Errors:  reportErrors(G.Errors),
+			Files:   []*FileReport{},
+			Deleted: G.Deleted,
+		}
+
+		for _, F := range G.Files {
+			if F.Filepath == "" {
+				continue
+			}
+			GR.Files = append(GR.Files, &FileReport{
+				Filepath: F.Filepath,
+				Status:   fileStatus(F),
+				Stats:    F.FileStats,
+				Errors:   reportErrors(F.Errors),
+			})
+		}
+		sort.Slice(GR.Files, func(i, j int) bool {
+			return GR.Files[i].Filepath < GR.Files[j].Filepath
+		})
+		sort.Strings(GR.Deleted)
+
+		report.Generators = append(report.Generators, GR)
+	}
+
+	return report
+}
+
+// CheckReportFlags errors for --report, --report-file, and --fail-on values
+// which cannot be used, before anything is generated or written
+func CheckReportFlags(cmdflags flags.GenPflagpole) error {
+	switch cmdflags.Report {
+	case "":
+		if cmdflags.ReportFile != "" {
+			return fmt.Errorf("--report-file needs a --report format, use one of [json, junit]")
+		}
+	case "json", "junit":
+		// stdout has the rest of the output, so the report would not parse
+		if cmdflags.ReportFile == "" {
+			return fmt.Errorf("--report needs a --report-file to write to")
+		}
+	default:
+		return fmt.Errorf("unknown report format %q, use one of [json, junit]", cmdflags.Report)
+	}
+
+	for _, cond := range cmdflags.FailOn {
+		switch cond {
+		case "conflict", "change":
+		default:
+			return fmt.Errorf("unknown --fail-on condition %q, use one of [conflict, change]", cond)
+		}
+	}
+
+	return nil
+}
+
+// WriteReport writes the report in the --report format to the --report-file, when requested
+func (R *Runtime) WriteReport(errs []error, elapsed time.Duration) error {
+	if R.Flagpole.Report == "" {
+		return nil
+	}
+
+	err := CheckReportFlags(R.Flagpole)
+	if err != nil {
+		return err
+	}
+
+	dir := path.Dir(R.Flagpole.ReportFile)
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(R.Flagpole.ReportFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	report := R.BuildReport(errs, elapsed)
+
+	if R.Flagpole.Report == "junit" {
+		return report.WriteJUnit(f)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// CheckPolicy errors when the output violates the --fail-on conditions,
+// which CheckReportFlags has already checked
+func (R *Runtime) CheckPolicy() error {
+	var numConflicts, numChanges int
+	for _, G := range R.Ordered {
+		if G.Disabled {
+			continue
+		}
+		G.Stats.CalcTotals(G)
+		numConflicts += G.Stats.NumConflicted
+		numChanges += G.Stats.NumWritten + G.Stats.NumDeleted
+	}
+
+	for _, cond := range R.Flagpole.FailOn {
+		switch cond {
+		case "conflict":
+			if numConflicts > 0 {
+				return fmt.Errorf("\n%d files have merge conflicts\n", numConflicts)
+			}
+		case "change":
+			if numChanges > 0 {
+				return fmt.Errorf("\n%d files were changed by generating\n", numChanges)
+			}
+		default:
+			return fmt.Errorf("unknown --fail-on condition %q, use one of [conflict, change]", cond)
+		}
+	}
+
+	return nil
+}
+
+//
+// JUnit, generators are suites and files are cases
+//
+
+type junitSuites struct {
+	XMLName  xml.Name      `xml:"testsuites"`
+	Name     string        `xml:"name,attr"`
+	Tests    int           `xml:"tests,attr"`
+	Failures int           `xml:"failures,attr"`
+	Errors   int           `xml:"errors,attr"`
+	Time     string        `xml:"time,attr"`
+	Suites   []*junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Cases    []*junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func junitTime(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+func junitErrors(errs []ReportError) *junitFailure {
+	if len(errs) == 0 {
+		return nil
+	}
+	var lines []string
+	for _, e := range errs {
+		if e.Filename != "" {
+			lines = append(lines, fmt.Sprintf("%s:%d:%d: %s", e.Filename, e.Line, e.Column, e.Message))
+		} else {
+			lines = append(lines, e.Message)
+		}
+	}
+	return &junitFailure{
+		Message: errs[0].Message,
+		Type:    "error",
+		Text:    strings.Join(lines, "\n"),
+	}
+}
+
+func (report *Report) WriteJUnit(w io.Writer) error {
+	suites := &junitSuites{
+		Name: "hof gen",
+		Time: junitTime(report.TotalTime),
+	}
+
+	// loading errors get their own suite, there are no generators yet
+	if len(report.Errors) > 0 {
+		suites.Suites = append(suites.Suites, &junitSuite{
+			Name:   "load",
+			Tests:  1,
+			Errors: 1,
+			Time:   junitTime(0),
+			Cases: []*junitCase{{
+				Name:      "load",
+				Classname: "load",
+				Time:      junitTime(0),
+				Error:     junitErrors(report.Errors),
+			}},
+		})
+	}
+
+	for _, GR := range report.Generators {
+		S := &junitSuite{
+			Name: GR.Name,
+			Time: junitTime(GR.Stats.TotalTime),
+		}
+
+		// errors for the generator as a whole
+		if len(GR.Errors) > 0 {
+			S.Cases = append(S.Cases, &junitCase{
+				Name:      GR.Name,
+				Classname: GR.Name,
+				Time:      junitTime(0),
+				Error:     junitErrors(GR.Errors),
+			})
+		}
+
+		for _, FR := range GR.Files {
+			C := &junitCase{
+				Name:      FR.Filepath,
+				Classname: GR.Name,
+				Time:      junitTime(FR.Stats.TotalTime),
+				SystemOut: FR.Status,
+			}
+			switch FR.Status {
+			case "error":
+				C.Error = junitErrors(FR.Errors)
+				if C.Error == nil {
+					C.Error = &junitFailure{Message: "error", Type: "error"}
+				}
+			case "conflict":
+				C.Failure = &junitFailure{
+					Message: "merge conflict in " + FR.Filepath,
+					Type:    "conflict",
+				}
+			case "skipped":
+				C.Skipped = &struct{}{}
+			}
+			S.Cases = append(S.Cases, C)
+		}
+
+		for _, fp := range GR.Deleted {
+			S.Cases = append(S.Cases, &junitCase{
+				Name:      fp,
+				Classname: GR.Name,
+				Time:      junitTime(0),
+				SystemOut: "deleted",
+			})
+		}
+
+		for _, C := range S.Cases {
+			S.Tests++
+			if C.Failure != nil {
+				S.Failures++
+			}
+			if C.Error != nil {
+				S.Errors++
+			}
+			if C.Skipped != nil {
+				S.Skipped++
+			}
+		}
+
+		suites.Suites = append(suites.Suites, S)
+	}
+
+	for _, S := range suites.Suites {
+		suites.Tests += S.Tests
+		suites.Failures += S.Failures
+		suites.Errors += S.Errors
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(suites)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,213 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"cuelang.org/go/cue"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+// reportRuntime has one file of each status, and a deleted file
+func reportRuntime(failOn ...string) *Runtime {
+	R := NewRuntime(nil, flags.GenPflagpole{FailOn: failOn})
+
+	G := gen.NewGenerator("G", cue.Value{})
+	add := func(fp string, set func(F *gen.File)) {
+		F := &gen.File{Filepath: fp}
+		set(F)
+		G.Files[fp] = F
+	}
+	add("new.txt", func(F *gen.File) { F.IsNew, F.IsWritten = 1, 1 })
+	add("same.txt", func(F *gen.File) { F.IsSame = 1 })
+	add("modified.txt", func(F *gen.File) { F.IsModified, F.IsModifiedDiff3, F.IsWritten = 1, 1, 1 })
+	add("conflict.txt", func(F *gen.File) { F.IsModified, F.IsConflicted = 1, 1 })
+	add("error.txt", func(F *gen.File) {
+		F.IsErr = 1
+		F.Errors = []error{fmt.Errorf("template: error.txt:3:12: executing")}
+	})
+	G.Deleted = []string{"old.txt"}
+	G.Stats.NumDeleted = 1
+
+	R.Generators[G.Name] = G
+	R.Ordered = []*gen.Generator{G}
+	return R
+}
+
+func TestReportJSON(t *testing.T) {
+	R := reportRuntime()
+	report := R.BuildReport([]error{fmt.Errorf("load error")}, time.Second)
+
+	data, err := json.Marshal(report)
+	assert.NoError(t, err)
+
+	var shape struct {
+		Generators []struct {
+			Name   string
+			Failed bool
+			Stats  map[string]interface{}
+			Files  []struct {
+				Filepath string
+				Status   string
+				Errors   []ReportError
+			}
+			Deleted []string
+		}
+		Errors    []ReportError
+		TotalTime int64
+	}
+	err = json.Unmarshal(data, &shape)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []ReportError{{Message: "load error"}}, shape.Errors)
+	assert.Equal(t, int64(time.Second), shape.TotalTime)
+	assert.Len(t, shape.Generators, 1)
+
+	GR := shape.Generators[0]
+	assert.Equal(t, "G", GR.Name)
+	assert.Equal(t, []string{"old.txt"}, GR.Deleted)
+	assert.Equal(t, 2.0, GR.Stats["NumWritten"])
+	assert.Equal(t, 1.0, GR.Stats["NumConflicted"])
+
+	statuses := map[string]string{}
+	for _, FR := range GR.Files {
+		statuses[FR.Filepath] = FR.Status
+	}
+	assert.Equal(t, map[string]string{
+		"conflict.txt": "conflict",
+		"error.txt":    "error",
+		"modified.txt": "modified",
+		"new.txt":      "new",
+		"same.txt":     "same",
+	}, statuses)
+
+	// sorted by filepath, template errors have their position
+	assert.Equal(t, "conflict.txt", GR.Files[0].Filepath)
+	assert.Equal(t, []ReportError{{
+		Message:  "template: error.txt:3:12: executing",
+		Filename: "error.txt",
+		Line:     3,
+		Column:   12,
+	}}, GR.Files[1].Errors)
+}
+
+func TestReportJUnit(t *testing.T) {
+	R := reportRuntime()
+	report := R.BuildReport([]error{fmt.Errorf("load error")}, time.Second)
+
+	var buf bytes.Buffer
+	err := report.WriteJUnit(&buf)
+	assert.NoError(t, err)
+
+	var suites junitSuites
+	err = xml.Unmarshal(buf.Bytes(), &suites)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 7, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 2, suites.Errors)
+	assert.Equal(t, "1.000", suites.Time)
+	assert.Len(t, suites.Suites, 2)
+
+	load := suites.Suites[0]
+	assert.Equal(t, "load", load.Name)
+	assert.Equal(t, 1, load.Errors)
+	assert.Equal(t, "load error", load.Cases[0].Error.Message)
+
+	S := suites.Suites[1]
+	assert.Equal(t, "G", S.Name)
+	assert.Equal(t, 6, S.Tests)
+	assert.Equal(t, 1, S.Failures)
+	assert.Equal(t, 1, S.Errors)
+
+	cases := map[string]*junitCase{}
+	for _, C := range S.Cases {
+		cases[C.Name] = C
+	}
+	assert.Equal(t, "conflict", cases["conflict.txt"].Failure.Type)
+	assert.Equal(t, "error.txt:3:12: template: error.txt:3:12: executing", cases["error.txt"].Error.Text)
+	assert.Equal(t, "deleted", cases["old.txt"].SystemOut)
+	assert.Nil(t, cases["same.txt"].Failure)
+	assert.Nil(t, cases["same.txt"].Error)
+}
+
+func TestCheckPolicy(t *testing.T) {
+	assert.NoError(t, reportRuntime().CheckPolicy())
+
+	err := reportRuntime("conflict").CheckPolicy()
+	assert.EqualError(t, err, "\n1 files have merge conflicts\n")
+
+	// written and deleted files are changes
+	err = reportRuntime("change").CheckPolicy()
+	assert.EqualError(t, err, "\n3 files were changed by generating\n")
+
+	err = reportRuntime("other").CheckPolicy()
+	assert.Error(t, err)
+
+	// no changes, nothing to fail on
+	R := reportRuntime("conflict", "change")
+	G := R.Ordered[0]
+	G.Files = map[string]*gen.File{"same.txt": G.Files["same.txt"]}
+	G.Stats.NumDeleted = 0
+	assert.NoError(t, R.CheckPolicy())
+}
+
+func TestCheckReportFlags(t *testing.T) {
+	cases := []struct {
+		Flags flags.GenPflagpole
+		Err   bool
+	}{
+		{flags.GenPflagpole{}, false},
+		{flags.GenPflagpole{Report: "json", ReportFile: "report.json"}, false},
+		{flags.GenPflagpole{Report: "junit", ReportFile: "report.xml", FailOn: []string{"conflict", "change"}}, false},
+		// stdout has the rest of the output
+		{flags.GenPflagpole{Report: "json"}, true},
+		{flags.GenPflagpole{ReportFile: "report.json"}, true},
+		{flags.GenPflagpole{Report: "yaml", ReportFile: "report.yaml"}, true},
+		{flags.GenPflagpole{FailOn: []string{"other"}}, true},
+	}
+
+	for _, c := range cases {
+		err := CheckReportFlags(c.Flags)
+		assert.Equal(t, c.Err, err != nil, "%+v %v", c.Flags, err)
+	}
+}
+
+func TestGenBadFlags(t *testing.T) {
+	defer genDir(t, runtimeFiles(`
+G: _ @gen(G)
+G: schema.#HofGenerator & {
+	PackageName: ""
+	Out: [schema.#HofGeneratorFile & {
+		Template: "hello"
+		Filepath: "out.txt"
+	}]
+}
+`))()
+
+	// nothing is written when the flags are wrong
+	err := Gen(nil, flags.GenPflagpole{FailOn: []string{"other"}})
+	assert.Error(t, err)
+	_, err = os.Stat("out.txt")
+	assert.True(t, os.IsNotExist(err))
+
+	err = Gen(nil, flags.GenPflagpole{Report: "json", ReportFile: "report.json", FailOn: []string{"change"}})
+	assert.Error(t, err)
+	_, err = os.Stat("out.txt")
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile("report.json")
+	assert.NoError(t, err)
+	var report Report
+	assert.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, "new", report.Generators[0].Files[0].Status)
+}
@@ -12,17 +12,15 @@ import (
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/load"
 	"github.com/fatih/color"
-	"github.com/mattn/go-zglob"
 
 	"github.com/hofstadter-io/hof/cmd/hof/flags"
 	"github.com/hofstadter-io/hof/lib/gen"
-	"github.com/hofstadter-io/hof/lib/yagu"
 )
 
 type Runtime struct {
 	// Setup options
 	Entrypoints []string
-	Flagpole flags.GenFlagpole
+	Flagpole flags.GenPflagpole
 
 	// TODO configuration
 	mode string
@@ -37,10 +35,14 @@ type Runtime struct {
 
 	// Hof related
 	Generators map[string]*gen.Generator
+	// Generators in the order to run them
+	Ordered []*gen.Generator
+	// Generators and files no longer owned by hof
+	Ejected map[string]bool
 	Shadow map[string]*gen.File
 }
 
-func NewRuntime(entrypoints [] string, cmdflags flags.GenFlagpole) (*Runtime) {
+func NewRuntime(entrypoints [] string, cmdflags flags.GenPflagpole) (*Runtime) {
 	return &Runtime {
 		Entrypoints: entrypoints,
 		Flagpole: cmdflags,
@@ -69,6 +71,13 @@ func (R *Runtime) LoadCue() []error {
 			continue
 		}
 
+		// Inject -t values into @tag() fields before building
+		err := injectTags(bi.Files, R.Flagpole.Tags)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
 		// Build the Instance
 		I, err := R.CueRT.Build(bi)
 		if err != nil {
@@ -79,6 +88,13 @@ func (R *Runtime) LoadCue() []error {
 			}
 			continue
 		}
+
+		// Then fill any --set values
+		I, err = applySets(I, R.Flagpole.Set)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
 		R.CueInstances = append(R.CueInstances, I)
 
 		// Get top level value from cuelang
@@ -121,29 +137,27 @@ func (R *Runtime) ExtractGenerators() {
 
 			// find top-level with gen attr
 			hasgen := false
+			match := true
 			for _, A := range attrs {
 				// does it have "@gen()"
 				if A.Name() == "gen" {
+					hasgen = true
 
 					// are there flags to match?
 					if len(R.Flagpole.Generator) > 0 {
 						vals := A.Vals()
-						match := false
+						match = false
 						for _, g := range R.Flagpole.Generator {
 							if _, ok := vals[g]; ok {
 								match = true
 								break
 							}
 						}
-
-						if !match {
-							continue
-						}
 					}
 
-					// passed, we should generate
-					hasgen = true
-					break
+					if match {
+						break
+					}
 				}
 			}
 
@@ -151,7 +165,10 @@ func (R *Runtime) ExtractGenerators() {
 				continue
 			}
 
+			// Unselected generators are kept, disabled, so dependencies on them resolve
 			G := gen.NewGenerator(label, value)
+			G.Disabled = !match
+			G.BatchSize = R.Flagpole.BatchSize
 			R.Generators[label] = G
 		}
 	}
@@ -172,10 +189,46 @@ func (R *Runtime) LoadGenerators() []error {
 			errs = append(errs, errsL...)
 			continue
 		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// Ejected generators and files are no longer ours
+	var err error
+	R.Ejected, err = gen.LoadEjected()
+	if err != nil {
+		return []error{err}
+	}
+	for _, G := range R.Generators {
+		G.RemoveEjected(R.Ejected)
+	}
+
+	// Dependencies are decoded, now we can order the generators
+	ordered, err := gen.OrderGenerators(R.Generators)
+	if err != nil {
+		return []error{err}
+	}
+	R.Ordered = ordered
+
+	for _, G := range R.Ordered {
+		if G.Disabled {
+			continue
+		}
+
+		// Select output before templates are parsed, to skip unneeded work
+		err := G.FilterFiles(R.fileFilter())
+		if err != nil {
+			errs = append(errs, err)
+			G.Fail()
+			continue
+		}
 
 		errsI := G.Initialize()
 		if len(errsI) != 0 {
 			errs = append(errs, errsI...)
+			G.Fail()
 			continue
 		}
 
@@ -186,177 +239,211 @@ func (R *Runtime) LoadGenerators() []error {
 
 }
 
-func (R *Runtime) RunGenerators() []error {
-	var errs []error
-	// var err error
+// RunGenerators renders and writes each generator in dependency order,
+// so a generator can consume the output of the generators it depends on.
+// Files are still written when some have errors, (issue #20)
+func (R *Runtime) RunGenerators() (errsG []error, errsW []error) {
 
-	/*
-	R.Shadow, err = gen.LoadShadow("", R.verbose)
-	if err != nil {
-		errs = append(errs, err)
-		return errs
+	// output filepath -> generator name, to detect collisions
+	claimed := map[string]string{}
+
+	// Check the outputs before anything is written, static globs are expanded
+	// against what is on disk now, and again after the generator's dependencies have run.
+	// Glob errors are reported when the static files are loaded.
+	for _, G := range R.Ordered {
+		if G.Disabled {
+			continue
+		}
+
+		fps := G.OutputPaths()
+		static, _ := G.StaticGlobPaths()
+		fps = append(fps, static...)
+
+		errsC := R.claimOutputs(G, claimed, fps)
+		if len(errsC) > 0 {
+			errsG = append(errsG, errsC...)
+			G.Fail(errsC...)
+		}
 	}
-	*/
 
-	// Load shadow, can this be done in parallel with the last step?
 	// Don't do in parallel yet, Cue is slow and hungry for memory @ v0.0.16
-	for _, G := range R.Generators {
-		if G.Disabled {
+	for _, G := range R.Ordered {
+		if G.Disabled || G.Failed {
+			continue
+		}
+
+		// Skip when a dependency had errors, its output may be incomplete
+		skip := false
+		for _, dep := range G.DependsOn {
+			if D := R.Generators[dep]; D.Failed {
+				err := fmt.Errorf("Skipping generator %q, dependency %q had errors", G.Name, dep)
+				errsG = append(errsG, err)
+				G.Fail(err)
+				skip = true
+				break
+			}
+		}
+		if skip {
+			continue
+		}
+
+		errsS := G.InitStaticFiles()
+		if len(errsS) > 0 {
+			errsG = append(errsG, errsS...)
+			G.Fail(errsS...)
+			continue
+		}
+
+		err := G.FilterFiles(R.fileFilter())
+		if err != nil {
+			errsG = append(errsG, err)
+			G.Fail(err)
+			continue
+		}
+		G.RemoveEjected(R.Ejected)
+
+		errsC := R.claimOutputs(G, claimed, G.OutputPaths())
+		if len(errsC) > 0 {
+			errsG = append(errsG, errsC...)
+			G.Fail(errsC...)
 			continue
 		}
 
 		shadow, err := gen.LoadShadow(G.Name, R.verbose)
 		if err != nil {
-			errs = append(errs, err)
-			return errs
+			errsG = append(errsG, err)
+			G.Fail(err)
+			continue
 		}
 
 		G.Shadow = shadow
-
-		errsG := G.GenerateFiles()
-		if len(errsG) > 0 {
-			errs = append(errs, errsG...)
+		err = G.FilterShadow()
+		if err != nil {
+			errsG = append(errsG, err)
+			G.Fail(err)
 			continue
 		}
 
+		// file errors are recorded on the files
+		var errs, errsF []error
+		if G.IsStreaming() {
+			errs, errsF = G.StreamFiles(func(files map[string]*gen.File) []error {
+				return R.writeFiles(G, files)
+			})
+		} else {
+			errs = G.GenerateFiles()
+			errsF = R.writeFiles(G, G.Files)
+		}
+		if len(errs) > 0 {
+			errsG = append(errsG, errs...)
+			G.Fail()
+		}
+
+		errsF = append(errsF, R.cleanShadow(G)...)
+		if len(errsF) > 0 {
+			errsW = append(errsW, errsF...)
+			G.Fail(errsF...)
+		}
 	}
 
-	return errs
+	return errsG, errsW
 }
 
-func (R *Runtime) WriteOutput() []error {
+// claimOutputs errors when a generator would write a file another generator already has,
+// both generators are marked as failed. Collisions found before any generator runs
+// mean neither writes anything. Static glob matches which a dependency creates are only
+// found when the generator runs, after the other generator may have written the file.
+func (R *Runtime) claimOutputs(G *gen.Generator, claimed map[string]string, fps []string) []error {
 	var errs []error
 
-
-	for _, G := range R.Generators {
-		if G.Disabled {
-			continue
+	for _, fp := range fps {
+		if other, ok := claimed[fp]; ok && other != G.Name {
+			err := fmt.Errorf("Output collision: generators %q and %q both write %q", other, G.Name, path.Clean(fp))
+			errs = append(errs, err)
+			// neither gets to write it
+			R.Generators[other].Fail(err)
 		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
 
-		writestart := time.Now()
+	for _, fp := range fps {
+		claimed[fp] = G.Name
+	}
 
-		// Order is important here for implicit overriding of content
+	return nil
+}
 
-		// Start with static file globs
-		for _, Glob := range G.StaticGlobs {
-			bdir := ""
-			if G.PackageName != "" {
-				bdir = path.Join("cue.mod/pkg", G.PackageName)
-			}
-			matches, err := zglob.Glob(path.Join(bdir, Glob))
+// writeFiles writes some or all of a generator's files, static files are included
+func (R *Runtime) writeFiles(G *gen.Generator, files map[string]*gen.File) []error {
+	var errs []error
+
+	writestart := time.Now()
+
+	for _, F := range files {
+		// Write the actual output
+		if F.DoWrite && len(F.Errors) == 0 {
+			err := F.WriteOutput()
 			if err != nil {
-				err = fmt.Errorf("while globbing %s / %s\n%w\n", bdir, Glob, err)
 				errs = append(errs, err)
 				continue
 			}
-			for _, match := range matches {
-				// trim first level directory
-				clean := Glob[:strings.Index(Glob, "/")]
-				mo := strings.TrimPrefix(match, clean)
-				src := path.Join(bdir, match)
-				dst := path.Join(G.Outdir, mo)
-
-				// TODO, make comparison and decide to write or not
-
-				// normal location
-				err := yagu.CopyFile(src, dst)
-				if err != nil {
-					err = fmt.Errorf("while copying static real file %q\n%w\n", match, err)
-					errs = append(errs, err)
-					continue
-				}
-
-				// shadow location
-				err = yagu.CopyFile(src, path.Join(".hof", G.Name, dst))
-				if err != nil {
-					err = fmt.Errorf("while copying static shadow file %q\n%w\n", match, err)
-					errs = append(errs, err)
-					continue
-				}
-
-				delete(R.Shadow, path.Join(G.Name, dst))
-				delete(G.Shadow, path.Join(G.Name, dst))
-				G.Stats.NumStatic += 1
-				G.Stats.NumWritten += 1
-
-			}
-
 		}
 
-		// Then the static files in cue
-		for p, content := range G.StaticFiles {
-			F := &gen.File {
-				Filepath: path.Join(G.Outdir, p),
-				FinalContent: []byte(content),
-			}
-			err := F.WriteOutput()
+		// Keep declared permissions up to date, even when the content is unchanged
+		if !F.DoWrite && len(F.Errors) == 0 {
+			err := F.ApplyMode()
 			if err != nil {
 				errs = append(errs, err)
 				continue
 			}
-			err = F.WriteShadow(path.Join(gen.SHADOW_DIR, G.Name))
+		}
+
+		// Write the shadow too, or if it doesn't exist
+		if F.DoWrite || (F.IsSame > 0 && F.ShadowFile == nil) {
+			err := F.WriteShadow(path.Join(gen.SHADOW_DIR, G.Name))
 			if err != nil {
 				errs = append(errs, err)
 				continue
 			}
-			delete(R.Shadow, path.Join(G.Name, F.Filepath))
-			delete(G.Shadow, path.Join(G.Name, F.Filepath))
-			G.Stats.NumStatic += 1
-			G.Stats.NumWritten += 1
 		}
 
-		// Finally write the generator files
-		for _, F := range G.Files {
-			// Write the actual output
-			if F.DoWrite && len(F.Errors) == 0 {
-				err := F.WriteOutput()
-				if err != nil {
-					errs = append(errs, err)
-					continue
-				}
-			}
+		// remove from shadows map so we can cleanup what remains
+		delete(R.Shadow, path.Join(G.Name, F.Filepath))
+		delete(G.Shadow, path.Join(G.Name, F.Filepath))
+	}
 
-			// Write the shadow too, or if it doesn't exist
-			if F.DoWrite || (F.IsSame > 0 && F.ShadowFile == nil) {
-				err := F.WriteShadow(path.Join(gen.SHADOW_DIR, G.Name))
-				if err != nil {
-					errs = append(errs, err)
-					continue
-				}
-			}
+	writeend := time.Now()
+	G.Stats.WritingTime += writeend.Sub(writestart).Round(time.Millisecond)
 
-			// remove from shadows map so we can cleanup what remains
-			delete(R.Shadow, path.Join(G.Name, F.Filepath))
-			delete(G.Shadow, path.Join(G.Name, F.Filepath))
-		}
+	return errs
+}
 
-		// Cleanup File & Shadow
-		// fmt.Println("Clean Shadow", G.Name)
-		for f, _ := range G.Shadow {
-			// fmt.Println("  -", G.Name, f, strings.TrimPrefix(f, G.Name + "/"))
-			err := os.Remove(f)
-			if err != nil {
-				if strings.Contains(err.Error(), "no such file or directory") {
-					continue
-				}
-				errs = append(errs, err)
-				continue
-			}
-			err = os.Remove(path.Join(gen.SHADOW_DIR, f))
-			if err != nil {
-				if strings.Contains(err.Error(), "no such file or directory") {
-					continue
-				}
-				errs = append(errs, err)
-				continue
-			}
-			G.Stats.NumDeleted += 1
-		}
+// cleanShadow removes the files and shadow the generator no longer has,
+// what remains in the shadow once all files are written
+func (R *Runtime) cleanShadow(G *gen.Generator) []error {
+	cleanstart := time.Now()
+
+	deleted, errs := G.RemoveOrphans()
+	G.Stats.NumDeleted += len(deleted)
+	G.Deleted = append(G.Deleted, deleted...)
+
+	cleanend := time.Now()
+	G.Stats.WritingTime += cleanend.Sub(cleanstart).Round(time.Millisecond)
 
-		writeend := time.Now()
-		G.Stats.WritingTime = writeend.Sub(writestart).Round(time.Millisecond)
+	return errs
+}
 
+func (R *Runtime) fileFilter() gen.FileFilter {
+	return gen.FileFilter{
+		Only:    R.Flagpole.Only,
+		Exclude: R.Flagpole.Exclude,
 	}
+}
+
+func (R *Runtime) WriteOutput() []error {
+	var errs []error
 
 	// Clean global shadow, incase any generators were removed
 	for f, _ := range R.Shadow {
@@ -393,7 +480,7 @@ func (R *Runtime) WriteOutput() []error {
 }
 
 func (R *Runtime) PrintStats() {
-	for _, G := range R.Generators {
+	for _, G := range R.Ordered {
 		if G.Disabled {
 			continue
 		}
@@ -405,7 +492,7 @@ func (R *Runtime) PrintStats() {
 }
 
 func (R *Runtime) PrintMergeConflicts() {
-	for _, G := range R.Generators {
+	for _, G := range R.Ordered {
 		if G.Disabled {
 			continue
 		}
@@ -0,0 +1,235 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+	"github.com/hofstadter-io/hof/lib/gen"
+	"github.com/hofstadter-io/hof/lib/templates"
+)
+
+// runtimeFiles is a module with the generators in gen.cue
+func runtimeFiles(generators string) map[string]string {
+	return map[string]string{
+		"cue.mod/module.cue": `module: "example.com/runtime"`,
+		"gen.cue": `
+package runtime
+
+import "github.com/hofstadter-io/hof/schema"
+
+` + generators,
+	}
+}
+
+// loadRuntime loads the generators, the errors are from Cue or the generators
+func loadRuntime(t *testing.T, generators string) (*Runtime, []error) {
+	defer genDir(t, runtimeFiles(generators))()
+
+	R := NewRuntime(nil, flags.GenPflagpole{})
+	if errs := R.LoadCue(); len(errs) > 0 {
+		return R, errs
+	}
+	return R, R.LoadGenerators()
+}
+
+type upperSystem struct{}
+
+func (S *upperSystem) Create(T *templates.Template) error {
+	T.Impl = strings.ToUpper(T.Source)
+	return nil
+}
+
+func (S *upperSystem) Render(T *templates.Template, data interface{}) ([]byte, error) {
+	return []byte(T.Impl.(string)), nil
+}
+
+func TestTemplateSystems(t *testing.T) {
+	templates.RegisterTemplateSystem("test-upper", &upperSystem{})
+
+	generator := func(config string) string {
+		return fmt.Sprintf(`
+G: _ @gen(G)
+G: schema.#HofGenerator & {
+	PackageName: ""
+	%s
+	Out: [schema.#HofGeneratorFile & {
+		Template: "hello"
+		Filepath: "out.txt"
+	}]
+}
+`, config)
+	}
+
+	// registered systems can be set from Cue
+	_, errs := loadRuntime(t, generator(`TemplateConfig: TemplateSystem: "test-upper"`))
+	assert.Empty(t, errs)
+
+	// unknown systems are errors when loading
+	_, errs = loadRuntime(t, generator(`TemplateConfig: TemplateSystem: "no-such-system"`))
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), `unknown TemplateSystem "no-such-system"`)
+	}
+
+	// data is only for files, the schema agrees with the loader
+	_, errs = loadRuntime(t, generator(`TemplateConfig: TemplateSystem: "data"`))
+	assert.NotEmpty(t, errs)
+}
+
+func TestClaimOutputs(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Claims map[string][]string // in generator name order
+		Failed []string
+	}{
+		{
+			Name:   "distinct",
+			Claims: map[string][]string{"A": {"a.txt"}, "B": {"b.txt"}},
+		},
+		{
+			Name:   "same generator twice",
+			Claims: map[string][]string{"A": {"a.txt", "a.txt"}},
+		},
+		{
+			Name:   "collision",
+			Claims: map[string][]string{"A": {"a.txt", "shared.txt"}, "B": {"shared.txt"}},
+			Failed: []string{"A", "B"},
+		},
+		{
+			Name:   "bystander",
+			Claims: map[string][]string{"A": {"shared.txt"}, "B": {"b.txt"}, "C": {"shared.txt"}},
+			Failed: []string{"A", "C"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			R := NewRuntime(nil, flags.GenPflagpole{})
+			claimed := map[string]string{}
+
+			var names []string
+			for name, _ := range c.Claims {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				G := gen.NewGenerator(name, cue.Value{})
+				R.Generators[name] = G
+				if errs := R.claimOutputs(G, claimed, c.Claims[name]); len(errs) > 0 {
+					G.Fail(errs...)
+				}
+			}
+
+			var failed []string
+			for _, name := range names {
+				if R.Generators[name].Failed {
+					failed = append(failed, name)
+				}
+			}
+			assert.Equal(t, c.Failed, failed)
+		})
+	}
+}
+
+func TestRunGeneratorsCollision(t *testing.T) {
+	defer genDir(t, runtimeFiles(`
+A: _ @gen(A)
+A: schema.#HofGenerator & {
+	PackageName: ""
+	Out: [schema.#HofGeneratorFile & {
+		Template: "a"
+		Filepath: "shared.txt"
+	}]
+}
+
+B: _ @gen(B)
+B: schema.#HofGenerator & {
+	PackageName: ""
+	Out: [schema.#HofGeneratorFile & {
+		Template: "b"
+		Filepath: "shared.txt"
+	}, schema.#HofGeneratorFile & {
+		Template: "b"
+		Filepath: "b.txt"
+	}]
+}
+`))()
+
+	R := NewRuntime(nil, flags.GenPflagpole{})
+	assert.Empty(t, R.LoadCue())
+	assert.Empty(t, R.LoadGenerators())
+
+	errsG, errsW := R.RunGenerators()
+	assert.Empty(t, errsW)
+	if assert.Len(t, errsG, 1) {
+		assert.Contains(t, errsG[0].Error(), `Output collision: generators "A" and "B" both write "shared.txt"`)
+	}
+	assert.True(t, R.Generators["A"].Failed)
+	assert.True(t, R.Generators["B"].Failed)
+
+	// found before running, so nothing is written
+	for _, fn := range []string{"shared.txt", "b.txt"} {
+		_, err := os.Stat(fn)
+		assert.True(t, os.IsNotExist(err), fn)
+	}
+}
+
+func TestRunGeneratorsOrphans(t *testing.T) {
+	files := runtimeFiles(`
+A: _ @gen(A)
+A: schema.#HofGenerator & {
+	PackageName: ""
+	Out: [schema.#HofGeneratorFile & {
+		Template: "a"
+		Filepath: "a.txt"
+	}]
+}
+
+B: _ @gen(B)
+B: schema.#HofGenerator & {
+	PackageName: ""
+	Out: [schema.#HofGeneratorFile & {
+		Template: "b"
+		Filepath: "b.txt"
+	}]
+}
+`)
+	// outputs the generators no longer have, unmodified since they were written
+	for _, fn := range []string{"a-old.txt", "a-kept/old.txt", "b-old.txt"} {
+		files[fn] = "old"
+	}
+	files[".hof/shadow/A/a-old.txt"] = "old"
+	files[".hof/shadow/A/a-kept/old.txt"] = "old"
+	files[".hof/shadow/B/b-old.txt"] = "old"
+	defer genDir(t, files)()
+
+	R := NewRuntime(nil, flags.GenPflagpole{Generator: []string{"A"}, Exclude: []string{"a-kept/*"}})
+	assert.Empty(t, R.LoadCue())
+	assert.Empty(t, R.LoadGenerators())
+
+	errsG, errsW := R.RunGenerators()
+	assert.Empty(t, errsG)
+	assert.Empty(t, errsW)
+	assert.Equal(t, []string{"a-old.txt"}, R.Generators["A"].Deleted)
+
+	exists := func(fn string) bool {
+		_, err := os.Stat(fn)
+		return err == nil
+	}
+
+	// only the selected generator's orphans, and only those matching the filters
+	assert.False(t, exists("a-old.txt"))
+	assert.False(t, exists(".hof/shadow/A/a-old.txt"))
+	assert.True(t, exists("a-kept/old.txt"))
+	assert.True(t, exists(".hof/shadow/A/a-kept/old.txt"))
+	assert.True(t, exists("b-old.txt"))
+	assert.True(t, exists(".hof/shadow/B/b-old.txt"))
+	assert.False(t, exists("b.txt"))
+}
@@ -0,0 +1,414 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"cuelang.org/go/cue/build"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+// GenServer keeps the Cue evaluation in memory between requests,
+// so editors and hooks can render and generate without reloading.
+// Cue is reloaded when any of the loaded Cue files change, including imports,
+// and generators are reloaded when their templates or data change.
+//
+//	GET  /generators                           list generators and their output files
+//	GET  /render?generator=<name>&filepath=<p> render a single file, nothing is written
+//	POST /gen                                  run the generators and write the output
+//	POST /reload                               reload the Cue files
+//
+// Requests must be to a local Host, or the address served on, and from a local Origin,
+// so web pages cannot reach the server by rebinding DNS. POSTs must send JSON, which
+// browsers will not send cross-origin without asking first, so pages cannot forge them.
+type GenServer struct {
+	sync.Mutex
+
+	Entrypoints []string
+	Flagpole    flags.GenPflagpole
+	Addr        string
+
+	R        *Runtime
+	LoadTime time.Time
+
+	// modification times of everything read while loading, by path
+	cueFiles map[string]time.Time
+	genFiles map[string]time.Time
+	// load errors, returned until something changes
+	loadErrs []error
+}
+
+func GenServe(args []string, cmdflags flags.GenPflagpole, addr string) error {
+	S := &GenServer{
+		Entrypoints: args,
+		Flagpole:    cmdflags,
+		Addr:        addr,
+	}
+
+	errs := S.reload()
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		return fmt.Errorf("\nErrors while loading cue files\n")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/generators", S.handleGenerators)
+	mux.HandleFunc("/render", S.handleRender)
+	mux.HandleFunc("/gen", S.handleGen)
+	mux.HandleFunc("/reload", S.handleReload)
+
+	fmt.Printf("hof gen serving on http://%s\n", addr)
+	return http.ListenAndServe(addr, S.checkOrigin(mux))
+}
+
+// checkOrigin rejects requests which are not to a local Host, or from a page which is not local
+func (S *GenServer) checkOrigin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !S.allowedHost(r.Host) {
+			writeJSON(w, http.StatusForbidden, genResponse{Errors: []string{fmt.Sprintf("host %q is not allowed", r.Host)}})
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || !S.allowedHost(u.Host) {
+				writeJSON(w, http.StatusForbidden, genResponse{Errors: []string{fmt.Sprintf("origin %q is not allowed", origin)}})
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost is true for loopback hosts and the host served on, when it was named
+func (S *GenServer) allowedHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	host = strings.Trim(host, "[]")
+	if host == "" {
+		return false
+	}
+
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	addr, _, err := net.SplitHostPort(S.Addr)
+	if err != nil {
+		return false
+	}
+	if ip := net.ParseIP(addr); addr == "" || (ip != nil && ip.IsUnspecified()) {
+		return false
+	}
+	return strings.EqualFold(host, addr)
+}
+
+// checkPost writes an error and returns false, unless r is a POST with a JSON body
+func checkPost(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, genResponse{Errors: []string{"use POST"}})
+		return false
+	}
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+		writeJSON(w, http.StatusUnsupportedMediaType, genResponse{Errors: []string{"use Content-Type: application/json"}})
+		return false
+	}
+	return true
+}
+
+type genResponse struct {
+	Generators map[string]*gen.GeneratorStats `json:"generators,omitempty"`
+	Errors     []string                       `json:"errors,omitempty"`
+}
+
+type generatorInfo struct {
+	Name   string   `json:"name"`
+	Outdir string   `json:"outdir"`
+	Files  []string `json:"files"`
+}
+
+type renderResponse struct {
+	Generator string `json:"generator"`
+	Filepath  string `json:"filepath"`
+	Content   string `json:"content"`
+}
+
+func (S *GenServer) handleGenerators(w http.ResponseWriter, r *http.Request) {
+	S.Lock()
+	defer S.Unlock()
+
+	if errs := S.refresh(); len(errs) > 0 {
+		writeJSON(w, http.StatusInternalServerError, genResponse{Errors: errorStrings(errs)})
+		return
+	}
+
+	infos := []generatorInfo{}
+	for _, G := range S.R.Ordered {
+		if G.Disabled {
+			continue
+		}
+		info := generatorInfo{
+			Name:   G.Name,
+			Outdir: G.Outdir,
+			Files:  G.OutputPaths(),
+		}
+		infos = append(infos, info)
+	}
+
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func (S *GenServer) handleRender(w http.ResponseWriter, r *http.Request) {
+	S.Lock()
+	defer S.Unlock()
+
+	if errs := S.refresh(); len(errs) > 0 {
+		writeJSON(w, http.StatusInternalServerError, genResponse{Errors: errorStrings(errs)})
+		return
+	}
+
+	name := r.URL.Query().Get("generator")
+	fp := path.Clean(r.URL.Query().Get("filepath"))
+
+	// any generator with the file, when not given
+	var F *gen.File
+	for _, G := range S.R.Ordered {
+		if G.Disabled || (name != "" && G.Name != name) {
+			continue
+		}
+		f, err := G.LookupFile(fp)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, genResponse{Errors: []string{err.Error()}})
+			return
+		}
+		if f != nil {
+			F, name = f, G.Name
+			break
+		}
+	}
+	if F == nil {
+		err := fmt.Errorf("file %q not found in any generator", fp)
+		if name != "" {
+			err = fmt.Errorf("file %q not found in generator %q", fp, name)
+		}
+		writeJSON(w, http.StatusNotFound, genResponse{Errors: []string{err.Error()}})
+		return
+	}
+
+	// render a copy, the loaded file is kept as is
+	C := *F
+	err := C.RenderTemplate()
+	if err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, genResponse{Errors: []string{err.Error()}})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, renderResponse{
+		Generator: name,
+		Filepath:  fp,
+		Content:   string(C.RenderContent),
+	})
+}
+
+func (S *GenServer) handleGen(w http.ResponseWriter, r *http.Request) {
+	if !checkPost(w, r) {
+		return
+	}
+
+	S.Lock()
+	defer S.Unlock()
+
+	if errs := S.refresh(); len(errs) > 0 {
+		writeJSON(w, http.StatusInternalServerError, genResponse{Errors: errorStrings(errs)})
+		return
+	}
+
+	errsG, errsW := S.R.RunGenerators()
+	errsW = append(errsW, S.R.WriteOutput()...)
+
+	resp := genResponse{
+		Generators: map[string]*gen.GeneratorStats{},
+		Errors:     errorStrings(append(errsG, errsW...)),
+	}
+	for _, G := range S.R.Ordered {
+		if G.Disabled {
+			continue
+		}
+		G.Stats.CalcTotals(G)
+		resp.Generators[G.Name] = G.Stats
+	}
+
+	// generating changes the generator files, go back to how they were loaded
+	for _, G := range S.R.Generators {
+		G.Reset()
+	}
+
+	status := http.StatusOK
+	if len(resp.Errors) > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSON(w, status, resp)
+}
+
+func (S *GenServer) handleReload(w http.ResponseWriter, r *http.Request) {
+	if !checkPost(w, r) {
+		return
+	}
+
+	S.Lock()
+	defer S.Unlock()
+
+	if errs := S.reload(); len(errs) > 0 {
+		writeJSON(w, http.StatusInternalServerError, genResponse{Errors: errorStrings(errs)})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, genResponse{})
+}
+
+// reload evaluates the Cue from scratch
+func (S *GenServer) reload() []error {
+	S.LoadTime = time.Now()
+	S.R = NewRuntime(S.Entrypoints, S.Flagpole)
+
+	S.loadErrs = S.R.LoadCue()
+	S.cueFiles = modTimes(S.cuePaths())
+	if len(S.loadErrs) > 0 {
+		return S.loadErrs
+	}
+
+	return S.loadGenerators()
+}
+
+// refresh reloads when Cue files changed, and otherwise
+// rebuilds the generators from the already evaluated Cue
+func (S *GenServer) refresh() []error {
+	if S.R == nil || changed(S.cueFiles, modTimes(S.cuePaths())) {
+		return S.reload()
+	}
+
+	if S.R.Generators != nil && !changed(S.genFiles, modTimes(S.genPaths())) {
+		return S.loadErrs
+	}
+
+	S.LoadTime = time.Now()
+	S.R.Generators = make(map[string]*gen.Generator)
+	S.R.Ordered = nil
+	S.R.ExtractGenerators()
+	return S.loadGenerators()
+}
+
+func (S *GenServer) loadGenerators() []error {
+	S.loadErrs = S.R.LoadGenerators()
+	for _, G := range S.R.Generators {
+		G.Snapshot()
+	}
+	S.genFiles = modTimes(S.genPaths())
+	return S.loadErrs
+}
+
+// cuePaths are the Cue files of the entrypoints and everything they import,
+// and those in their directories, so files added to a package are noticed
+func (S *GenServer) cuePaths() []string {
+	var paths []string
+	seen := map[*build.Instance]bool{}
+
+	var walk func(bi *build.Instance)
+	walk = func(bi *build.Instance) {
+		if bi == nil || seen[bi] {
+			return
+		}
+		seen[bi] = true
+		for _, f := range bi.Files {
+			paths = append(paths, f.Filename)
+		}
+		if infos, err := ioutil.ReadDir(bi.Dir); err == nil {
+			for _, info := range infos {
+				if !info.IsDir() && strings.HasSuffix(info.Name(), ".cue") {
+					paths = append(paths, filepath.Join(bi.Dir, info.Name()))
+				}
+			}
+		}
+		for _, imp := range bi.Imports {
+			walk(imp)
+		}
+	}
+	for _, bi := range S.R.BuildInstances {
+		walk(bi)
+	}
+
+	return paths
+}
+
+// genPaths are the templates, data, and other files the generators read
+func (S *GenServer) genPaths() []string {
+	paths := []string{gen.EJECTED_FILE}
+	for _, G := range S.R.Generators {
+		paths = append(paths, G.SourcePaths()...)
+	}
+	return paths
+}
+
+// modTimes walks the paths, directories are included so added and removed files
+// are noticed, missing paths have the zero time
+func modTimes(paths []string) map[string]time.Time {
+	times := make(map[string]time.Time, len(paths))
+	for _, p := range paths {
+		times[p] = time.Time{}
+		filepath.Walk(p, func(fp string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			times[fp] = info.ModTime()
+			return nil
+		})
+	}
+	return times
+}
+
+func changed(before, after map[string]time.Time) bool {
+	if len(before) != len(after) {
+		return true
+	}
+	for p, t := range after {
+		if b, ok := before[p]; !ok || !b.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func errorStrings(errs []error) []string {
+	strs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		strs = append(strs, e.Error())
+	}
+	return strs
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(data)
+}
@@ -0,0 +1,230 @@
+package lib
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+)
+
+var serveFiles = map[string]string{
+	"cue.mod/module.cue": `module: "example.com/serve"`,
+	"gen.cue": `
+package serve
+
+import (
+	"github.com/hofstadter-io/hof/schema"
+	"example.com/serve/vals"
+)
+
+G: _ @gen(G)
+G: schema.#HofGenerator & {
+	Outdir:       "out"
+	PackageName:  ""
+	TemplatesDir: "templates"
+	PartialsDir:  "partials"
+	In: Val: vals.Val
+	Out: [schema.#HofGeneratorFile & {
+		TemplateName: "a.txt"
+		Filepath:     "out/a.txt"
+	}, schema.#HofGeneratorFile & {
+		Template: "b={{ .Val }}"
+		Filepath: "out/b.txt"
+	}]
+}
+`,
+	"vals/vals.cue":   "package vals\nVal: 1\n",
+	"templates/a.txt": `a={{ .Val }} {{ template "p.txt" . }}`,
+	"partials/p.txt":  "p",
+}
+
+// serveDir sets up a generator to serve
+func serveDir(t *testing.T) func() {
+	return genDir(t, serveFiles)
+}
+
+// genDir sets up the files in a temporary directory, with the hof schema from this repository
+func genDir(t *testing.T, files map[string]string) func() {
+	wd, _ := os.Getwd()
+	schema, err := filepath.Abs(filepath.Join(wd, "..", "schema"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "hof-serve-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Chdir(dir)
+
+	for fn, content := range files {
+		editFile(t, fn, content)
+	}
+	pkg := "cue.mod/pkg/github.com/hofstadter-io/hof"
+	os.MkdirAll(pkg, 0755)
+	if err := os.Symlink(schema, filepath.Join(pkg, "schema")); err != nil {
+		t.Fatal(err)
+	}
+
+	return func() {
+		os.Chdir(wd)
+		os.RemoveAll(dir)
+	}
+}
+
+// editFile writes a file, with a later modification time so the change is seen
+func editFile(t *testing.T, fn, content string) {
+	os.MkdirAll(filepath.Dir(fn), 0755)
+	if err := ioutil.WriteFile(fn, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(fn, later, later)
+}
+
+func serveRequest(t *testing.T, handler http.HandlerFunc, method, url string, resp interface{}) int {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, url, nil)
+	if method == "POST" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	handler(w, r)
+	if resp != nil {
+		err := json.Unmarshal(w.Body.Bytes(), resp)
+		assert.NoError(t, err, w.Body.String())
+	}
+	return w.Code
+}
+
+func newServer(t *testing.T, batchSize int) *GenServer {
+	S := &GenServer{Flagpole: flags.GenPflagpole{BatchSize: batchSize}}
+	errs := S.reload()
+	assert.Empty(t, errs)
+	return S
+}
+
+func renderFile(t *testing.T, S *GenServer, fp string) string {
+	var resp renderResponse
+	code := serveRequest(t, S.handleRender, "GET", "/render?generator=G&filepath="+fp, &resp)
+	assert.Equal(t, http.StatusOK, code)
+	return resp.Content
+}
+
+func TestServeGenerators(t *testing.T) {
+	defer serveDir(t)()
+	S := newServer(t, 0)
+
+	var infos []generatorInfo
+	code := serveRequest(t, S.handleGenerators, "GET", "/generators", &infos)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []generatorInfo{{Name: "G", Outdir: "out", Files: []string{"out/a.txt", "out/b.txt"}}}, infos)
+
+	var resp genResponse
+	code = serveRequest(t, S.handleRender, "GET", "/render?filepath=out/c.txt", &resp)
+	assert.Equal(t, http.StatusNotFound, code)
+	code = serveRequest(t, S.handleGen, "GET", "/gen", &resp)
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+
+	// a form, which a web page can post cross-origin without asking
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/gen", nil)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	S.handleGen(w, r)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
+
+func TestServeRefresh(t *testing.T) {
+	defer serveDir(t)()
+	S := newServer(t, 0)
+
+	assert.Equal(t, "a=1 p", renderFile(t, S, "out/a.txt"))
+
+	// templates, partials, and imported Cue are all watched
+	editFile(t, "templates/a.txt", `A={{ .Val }} {{ template "p.txt" . }}`)
+	assert.Equal(t, "A=1 p", renderFile(t, S, "out/a.txt"))
+
+	editFile(t, "partials/p.txt", "P")
+	assert.Equal(t, "A=1 P", renderFile(t, S, "out/a.txt"))
+
+	editFile(t, "vals/vals.cue", "package vals\nVal: 2\n")
+	assert.Equal(t, "A=2 P", renderFile(t, S, "out/a.txt"))
+	assert.Equal(t, "b=2", renderFile(t, S, "out/b.txt"))
+
+	// files added to a package are noticed
+	editFile(t, "vals/vals.cue", "package vals\nVal: *1 | int\n")
+	assert.Equal(t, "b=1", renderFile(t, S, "out/b.txt"))
+	editFile(t, "vals/more.cue", "package vals\nVal: 3\n")
+	assert.Equal(t, "b=3", renderFile(t, S, "out/b.txt"))
+}
+
+func TestServeOrigin(t *testing.T) {
+	S := &GenServer{Addr: "localhost:2323"}
+	handler := S.checkOrigin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	cases := []struct {
+		Host   string
+		Origin string
+		Status int
+	}{
+		{"localhost:2323", "", http.StatusOK},
+		{"127.0.0.1:2323", "", http.StatusOK},
+		{"[::1]:2323", "", http.StatusOK},
+		{"localhost:2323", "http://localhost:3000", http.StatusOK},
+		// DNS rebinding, the page's name resolves to the loopback address
+		{"attacker.example.com:2323", "", http.StatusForbidden},
+		// a web page posting to the server
+		{"localhost:2323", "http://attacker.example.com", http.StatusForbidden},
+		{"localhost:2323", "null", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/gen", nil)
+		r.Host = c.Host
+		if c.Origin != "" {
+			r.Header.Set("Origin", c.Origin)
+		}
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, c.Status, w.Code, "%s %s", c.Host, c.Origin)
+	}
+
+	// a named address is allowed, a wildcard is not
+	S.Addr = "devbox:2323"
+	assert.True(t, S.allowedHost("devbox:2323"))
+	S.Addr = "0.0.0.0:2323"
+	assert.False(t, S.allowedHost("0.0.0.0:2323"))
+}
+
+func TestServeGen(t *testing.T) {
+	for _, batchSize := range []int{0, 1} {
+		func() {
+			defer serveDir(t)()
+			S := newServer(t, batchSize)
+			G := S.R.Generators["G"]
+
+			for i := 0; i < 2; i++ {
+				var resp genResponse
+				code := serveRequest(t, S.handleGen, "POST", "/gen", &resp)
+				assert.Equal(t, http.StatusOK, code, "%v", resp.Errors)
+				assert.Equal(t, 2, resp.Generators["G"].NumWritten+resp.Generators["G"].NumSame)
+
+				content, err := ioutil.ReadFile("out/b.txt")
+				assert.NoError(t, err)
+				assert.Equal(t, "b=1", string(content))
+
+				// the loaded generator is kept, and files can still be rendered
+				assert.True(t, G == S.R.Generators["G"], "batch size %d", batchSize)
+				assert.Equal(t, "a=1 p", renderFile(t, S, "out/a.txt"))
+			}
+		}()
+	}
+}
@@ -0,0 +1,86 @@
+package lib
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/cmd/hof/flags"
+	"github.com/hofstadter-io/hof/lib/gen"
+)
+
+var streamFiles = map[string]string{
+	"cue.mod/module.cue": `module: "example.com/stream"`,
+	"gen.cue": `
+package stream
+
+import "github.com/hofstadter-io/hof/schema"
+
+G: _ @gen(G)
+G: schema.#HofGenerator & {
+	Outdir:      "out"
+	PackageName: ""
+	StaticFiles: "static.txt": "static"
+	Out: [ for i in ["1", "2", "3", "4"] {
+		schema.#HofGeneratorFile & {
+			In: N: i
+			Template: "n={{ .N }}"
+			Filepath: "out/\(i).txt"
+		}
+	}]
+}
+`,
+}
+
+func TestStreamFiles(t *testing.T) {
+	defer genDir(t, streamFiles)()
+
+	R := NewRuntime(nil, flags.GenPflagpole{BatchSize: 2})
+	assert.Empty(t, R.LoadCue())
+	assert.Empty(t, R.LoadGenerators())
+
+	G := R.Generators["G"]
+	assert.True(t, G.IsStreaming())
+
+	// only the filepaths are known until streaming
+	assert.Empty(t, G.Files)
+	assert.Equal(t, []string{"out/1.txt", "out/2.txt", "out/3.txt", "out/4.txt"}, G.OutputPaths())
+
+	assert.Empty(t, G.InitStaticFiles())
+
+	var batches [][]string
+	errsG, errsW := G.StreamFiles(func(files map[string]*gen.File) []error {
+		var batch []string
+		for fp, F := range files {
+			batch = append(batch, fp)
+			assert.NotNil(t, F.FinalContent, fp)
+		}
+		batches = append(batches, batch)
+		return R.writeFiles(G, files)
+	})
+	assert.Empty(t, errsG)
+	assert.Empty(t, errsW)
+
+	// static files go first, then Out in order, a batch at a time
+	assert.Len(t, batches, 3)
+	assert.ElementsMatch(t, []string{"out/static.txt", "out/1.txt"}, batches[0])
+	assert.ElementsMatch(t, []string{"out/2.txt", "out/3.txt"}, batches[1])
+	assert.ElementsMatch(t, []string{"out/4.txt"}, batches[2])
+
+	// written files are released, the stats remain
+	assert.Len(t, G.Files, 5)
+	for fp, F := range G.Files {
+		assert.Nil(t, F.FinalContent, fp)
+		assert.Nil(t, F.RenderContent, fp)
+		assert.Nil(t, F.TemplateInstance, fp)
+		assert.Equal(t, 1, F.IsNew, fp)
+		assert.Equal(t, 1, F.IsWritten, fp)
+	}
+
+	for _, n := range []string{"1", "2", "3", "4"} {
+		content, err := ioutil.ReadFile("out/" + n + ".txt")
+		assert.NoError(t, err)
+		assert.Equal(t, "n="+n, string(content))
+	}
+}
@@ -0,0 +1,206 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/token"
+)
+
+// A field marked for injection with @tag(name, [type=int|number|bool|string], [short=a|b])
+type tagField struct {
+	Field *ast.Field
+	Name  string
+	Type  string
+	Short []string
+}
+
+// injectTags unifies values from -t flags into the fields marked with @tag().
+// Flags are either key=value or a short value listed in a tag's short option.
+func injectTags(files []*ast.File, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	fields := []*tagField{}
+	for _, file := range files {
+		ast.Walk(file, func(n ast.Node) bool {
+			F, ok := n.(*ast.Field)
+			if !ok {
+				return true
+			}
+			for _, A := range F.Attrs {
+				if tf := parseTagAttr(A.Text); tf != nil {
+					tf.Field = F
+					fields = append(fields, tf)
+				}
+			}
+			return true
+		}, nil)
+	}
+
+	for _, tag := range tags {
+		key, val := tag, ""
+		short := true
+		if pos := strings.Index(tag, "="); pos >= 0 {
+			key, val = tag[:pos], tag[pos+1:]
+			short = false
+		}
+
+		found := false
+		for _, tf := range fields {
+			if short {
+				for _, s := range tf.Short {
+					if s == key {
+						found = true
+						if err := tf.inject(key); err != nil {
+							return err
+						}
+					}
+				}
+				continue
+			}
+			if tf.Name == key {
+				found = true
+				if err := tf.inject(val); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("no @tag() field for tag %q", tag)
+		}
+	}
+
+	return nil
+}
+
+func parseTagAttr(text string) *tagField {
+	if !strings.HasPrefix(text, "@tag(") || !strings.HasSuffix(text, ")") {
+		return nil
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(text, "@tag("), ")")
+	parts := strings.Split(body, ",")
+
+	tf := &tagField{
+		Name: strings.TrimSpace(parts[0]),
+		Type: "string",
+	}
+	for _, p := range parts[1:] {
+		p = strings.TrimSpace(p)
+		switch {
+		case strings.HasPrefix(p, "type="):
+			tf.Type = strings.TrimPrefix(p, "type=")
+		case strings.HasPrefix(p, "short="):
+			tf.Short = strings.Split(strings.TrimPrefix(p, "short="), "|")
+		}
+	}
+
+	if tf.Name == "" {
+		return nil
+	}
+	return tf
+}
+
+func (tf *tagField) inject(val string) error {
+	var expr ast.Expr
+
+	switch tf.Type {
+	case "string":
+		expr = ast.NewString(val)
+
+	case "int", "number", "bool":
+		var err error
+		switch tf.Type {
+		case "int":
+			_, err = strconv.ParseInt(val, 0, 64)
+		case "number":
+			_, err = strconv.ParseFloat(val, 64)
+		case "bool":
+			_, err = strconv.ParseBool(val)
+		}
+		if err == nil {
+			expr, err = parser.ParseExpr("tag", val)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q for tag %q", tf.Type, val, tf.Name)
+		}
+
+	default:
+		return fmt.Errorf("unsupported type %q for tag %q", tf.Type, tf.Name)
+	}
+
+	// unify, so the field's constraints still apply
+	tf.Field.Value = ast.NewBinExpr(token.AND, tf.Field.Value, expr)
+	return nil
+}
+
+// applySets fills values from --set path[:type]=value flags into the instance.
+// Values are strings, unless quoted, which are unquoted like a Go or JSON string,
+// or typed with one of int, number, bool, or json, (i.e. --set replicas:int=3).
+// Filling unifies, so only open fields can be set, a concrete value in the Cue is not overridden.
+func applySets(I *cue.Instance, sets []string) (*cue.Instance, error) {
+	for _, set := range sets {
+		pos := strings.Index(set, "=")
+		if pos < 1 {
+			return nil, fmt.Errorf("invalid --set %q, should be path[:type]=value", set)
+		}
+		path, raw := set[:pos], set[pos+1:]
+
+		typ := "string"
+		if tpos := strings.LastIndex(path, ":"); tpos >= 0 {
+			path, typ = path[:tpos], path[tpos+1:]
+		}
+
+		val, err := setValue(typ, raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --set %q, %w", set, err)
+		}
+
+		I, err = I.Fill(val, strings.Split(path, ".")...)
+		if err == nil {
+			err = I.Value().Lookup(strings.Split(path, ".")...).Err()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("while setting %q, only open fields can be set\n%w", set, err)
+		}
+	}
+
+	return I, nil
+}
+
+func setValue(typ, raw string) (interface{}, error) {
+	var (
+		val interface{}
+		err error
+	)
+
+	switch typ {
+	case "string":
+		if len(raw) >= 2 && raw[0] == '"' && raw[len(raw)-1] == '"' {
+			return strconv.Unquote(raw)
+		}
+		return raw, nil
+	case "int":
+		val, err = strconv.ParseInt(raw, 0, 64)
+	case "number":
+		val, err = strconv.ParseFloat(raw, 64)
+	case "bool":
+		val, err = strconv.ParseBool(raw)
+	case "json":
+		err = json.Unmarshal([]byte(raw), &val)
+	default:
+		return nil, fmt.Errorf("unsupported type %q", typ)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("not a valid %s: %q", typ, raw)
+	}
+	return val, nil
+}
@@ -0,0 +1,99 @@
+package lib
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+const tagsSrc = `
+env:      *"dev" | "prod" @tag(env, short=dev|prod)
+replicas: int           @tag(replicas, type=int)
+debug:    bool | *false @tag(debug, type=bool)
+version:  string
+count:    int
+name:     "fixed"
+`
+
+func compileTags(t *testing.T, tags []string) (*cue.Instance, error) {
+	f, err := parser.ParseFile("tags.cue", tagsSrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = injectTags([]*ast.File{f}, tags)
+	if err != nil {
+		return nil, err
+	}
+	var r cue.Runtime
+	return r.CompileFile(f)
+}
+
+func TestInjectTags(t *testing.T) {
+	I, err := compileTags(t, []string{"prod", "replicas=3", "debug=true"})
+	assert.NoError(t, err)
+
+	env, _ := I.Lookup("env").String()
+	assert.Equal(t, "prod", env)
+	replicas, _ := I.Lookup("replicas").Int64()
+	assert.Equal(t, int64(3), replicas)
+	debug, _ := I.Lookup("debug").Bool()
+	assert.True(t, debug)
+
+	// the default is kept without a tag
+	I, err = compileTags(t, nil)
+	assert.NoError(t, err)
+	env, _ = I.Lookup("env").String()
+	assert.Equal(t, "dev", env)
+
+	for _, tags := range [][]string{
+		{"replicas=three"},
+		{"debug=yes"},
+		{"missing=1"},
+		{"staging"},
+	} {
+		_, err = compileTags(t, tags)
+		assert.Error(t, err, "%v", tags)
+	}
+
+	// the field's constraints still apply
+	I, err = compileTags(t, []string{"env=test"})
+	if err == nil {
+		err = I.Value().Validate(cue.Concrete(true))
+	}
+	assert.Error(t, err)
+}
+
+func TestApplySets(t *testing.T) {
+	I, err := compileTags(t, nil)
+	assert.NoError(t, err)
+
+	I, err = applySets(I, []string{"version=1.0", "count:int=2", `name="fixed"`})
+	assert.NoError(t, err)
+
+	version, err := I.Lookup("version").String()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", version)
+	count, _ := I.Lookup("count").Int64()
+	assert.Equal(t, int64(2), count)
+
+	I, err = applySets(I, []string{`extra:json={"a":[1,2]}`, `quoted="a b"`})
+	assert.NoError(t, err)
+	quoted, _ := I.Lookup("quoted").String()
+	assert.Equal(t, "a b", quoted)
+	a, _ := I.Lookup("extra", "a").List()
+	assert.True(t, a.Next())
+
+	for _, set := range []string{
+		"name=other",    // concrete values are not overridden
+		"version:int=1", // conflicts with the first --set
+		"count:int=two",
+		"count:float=2",
+		"=value",
+	} {
+		_, err = applySets(I, []string{set})
+		assert.Error(t, err, set)
+	}
+}
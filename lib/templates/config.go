@@ -45,6 +45,10 @@ type Config struct {
   RHS2_T string
   LHS3_T string
   RHS3_T string
+
+	// Extra helpers, registered after (and overriding) the builtin helpers
+	//   these are usually user defined helpers from a generator
+	Helpers map[string]interface{}
 }
 
 func (D *Config) SwitchBefore(content string) string {
@@ -138,4 +142,8 @@ func (D *Config) OverrideDotDefaults(delim *Config) {
 		D.RHS3_T = delim.RHS3_T
 	}
 
+	if D.Helpers == nil {
+		D.Helpers = delim.Helpers
+	}
+
 }
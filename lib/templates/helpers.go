@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"reflect"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -21,18 +22,85 @@ func AddGolangHelpers(t *template.Template) (*template.Template) {
 	return t.Funcs(funcMap)
 }
 
-func AddRaymondHelpers(t *raymond.Template) (*raymond.Template) {
+// Raymond panics on duplicate helper names,
+// so the extra helpers are merged over the builtins first
+func AddRaymondHelpers(t *raymond.Template, extra ...map[string]interface{}) (*raymond.Template) {
+	helpers := make(map[string]interface{}, len(funcMap))
 	for k, f := range funcMap {
-		if k == "dref" {
-			t.RegisterHelper(k, Helper_dref_raymond)
+		if r, ok := raymondMap[k]; ok {
+			helpers[k] = r
 			continue
 		}
+		helpers[k] = raymondHelper(f)
+	}
+	for k, f := range raymondLayoutHelpers {
+		helpers[k] = f
+	}
+	for _, E := range extra {
+		for k, f := range E {
+			helpers[k] = raymondHelper(f)
+		}
+	}
+
+	for k, f := range helpers {
 		t.RegisterHelper(k, f)
 	}
 
 	return t
 }
 
+// Raymond specific implementations for helpers which
+// need the options or can not be called as in golang
+var raymondMap = map[string]interface{} {
+	"dref": Helper_dref_raymond,
+	"dict": Helper_dict_raymond,
+}
+
+// Raymond only calls helpers with a fixed number of arguments,
+// so variadic helpers are wrapped and take the variadic part
+// from the hash arguments, sorted by key rather than as written.
+// {{ list z=1 a=2 }} is [2 1], name the keys in order, i.e. {{ list a=1 b=2 }}.
+// This is the one difference from the golang system, where they are positional.
+func raymondHelper(f interface{}) interface{} {
+	fv := reflect.ValueOf(f)
+	ft := fv.Type()
+	if !ft.IsVariadic() {
+		return f
+	}
+
+	nfixed := ft.NumIn() - 1
+	in := []reflect.Type{}
+	for i := 0; i < nfixed; i++ {
+		in = append(in, ft.In(i))
+	}
+	in = append(in, reflect.TypeOf(&raymond.Options{}))
+	out := []reflect.Type{ft.Out(0)}
+
+	wt := reflect.FuncOf(in, out, false)
+	wf := reflect.MakeFunc(wt, func(args []reflect.Value) []reflect.Value {
+		options := args[nfixed].Interface().(*raymond.Options)
+		hash := options.Hash()
+		keys := make([]string, 0, len(hash))
+		for k, _ := range hash {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		elemType := ft.In(nfixed).Elem()
+		call := args[:nfixed]
+		for _, k := range keys {
+			v := reflect.ValueOf(hash[k])
+			if !v.IsValid() {
+				v = reflect.Zero(elemType)
+			}
+			call = append(call, v)
+		}
+		return fv.Call(call)
+	})
+
+	return wf.Interface()
+}
+
 var funcMap = template.FuncMap {
 	"concat2": Helper_concat2,
 	"concat3": Helper_concat3,
@@ -108,12 +176,95 @@ var funcMap = template.FuncMap {
 	"int_lte": Helper_int_lte,
 	"int_lt": Helper_int_lt,
 
-	"add": Helper_add,
-	"inc": Helper_inc,
-
 	"file": Helper_file,
 
 	"dref": Helper_dref_golang,
+
+	// collections
+	"dict": Helper_dict,
+	"list": Helper_list,
+	"keys": Helper_keys,
+	"values": Helper_values,
+	"haskey": Helper_haskey,
+	"get": Helper_get,
+	"put": Helper_put,
+	"pick": Helper_pick,
+	"omit": Helper_omit,
+	"merge": Helper_merge,
+	"append": Helper_append,
+	"prepend": Helper_prepend,
+	"first": Helper_first,
+	"last": Helper_last,
+	"rest": Helper_rest,
+	"has": Helper_has,
+	"uniq": Helper_uniq,
+	"union": Helper_union,
+	"intersect": Helper_intersect,
+	"difference": Helper_difference,
+	"sortstr": Helper_sortstr,
+	"sortby": Helper_sortby,
+	"rsortby": Helper_rsortby,
+	"pluck": Helper_pluck,
+
+	// math
+	"add": Helper_add,
+	"inc": Helper_inc,
+	"sub": Helper_sub,
+	"mul": Helper_mul,
+	"div": Helper_div,
+	"mod": Helper_mod,
+	"dec": Helper_dec,
+	"max": Helper_max,
+	"min": Helper_min,
+	"sum": Helper_sum,
+	"floor": Helper_floor,
+	"ceil": Helper_ceil,
+	"round": Helper_round,
+	"int": Helper_int,
+	"float": Helper_float,
+	"seq": Helper_seq,
+
+	// regex
+	"regex_match": Helper_regex_match,
+	"regex_find": Helper_regex_find,
+	"regex_findall": Helper_regex_findall,
+	"regex_replace": Helper_regex_replace,
+	"regex_split": Helper_regex_split,
+
+	// date & time
+	"now": Helper_now,
+	"date": Helper_date,
+	"date_parse": Helper_date_parse,
+	"date_add": Helper_date_add,
+	"unix": Helper_unix,
+
+	// hashing, uuids, encodings
+	"md5": Helper_md5,
+	"sha1": Helper_sha1,
+	"sha256": Helper_sha256,
+	"sha512": Helper_sha512,
+	"uuid": Helper_uuid,
+	"uuid5": Helper_uuid5,
+	"b64enc": Helper_b64enc,
+	"b64dec": Helper_b64dec,
+	"jsoninline": Helper_jsoninline,
+
+	// pluralization
+	"plural": Helper_plural,
+	"singular": Helper_singular,
+	"pluralize": Helper_pluralize,
+
+	// code helpers
+	"indentn": Helper_indentn,
+	"nindent": Helper_nindent,
+	"dedent": Helper_dedent,
+	"comment": Helper_comment,
+	"wrap": Helper_wrap,
+	"spaces": Helper_spaces,
+	"tabs": Helper_tabs,
+	"trim": Helper_trim,
+	"quote": Helper_quote,
+	"squote": Helper_squote,
 }
 
 
@@ -560,14 +711,6 @@ func Helper_int_lte(lhs, rhs int) interface{} {
   return nil
 }
 
-func Helper_add(lhs, rhs int) int {
-	return lhs + rhs
-}
-
-func Helper_inc(val int) int {
-	return val + 1
-}
-
 func Helper_file(filename string) string {
 	body, err := ioutil.ReadFile(filename)
 
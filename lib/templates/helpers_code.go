@@ -0,0 +1,217 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//
+// Indentation aware helpers for generating code
+//
+
+// Helper_indentn indents every line by n spaces
+func Helper_indentn(value string, n interface{}) string {
+	return Helper_indent(value, Helper_spaces(n))
+}
+
+// Helper_nindent is indent with a leading newline, so it can start on the line of the tag
+func Helper_nindent(value, indent string) string {
+	return "\n" + Helper_indent(value, indent)
+}
+
+// Helper_dedent removes the whitespace prefix common to all non-empty lines
+func Helper_dedent(value string) string {
+	lines := strings.Split(value, "\n")
+	prefix := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		ws := line[:len(line)-len(strings.TrimLeftFunc(line, unicode.IsSpace))]
+		if first {
+			prefix = ws
+			first = false
+			continue
+		}
+		for !strings.HasPrefix(ws, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, prefix)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Helper_comment prefixes every line with a comment marker, like "// " or "# "
+func Helper_comment(value, prefix string) string {
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " \t")
+			continue
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Helper_wrap wraps text at word boundaries so lines are at most width long
+func Helper_wrap(value string, width interface{}) string {
+	w := int(num(width))
+	var out []string
+	for _, para := range strings.Split(value, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			if line != "" && len(line)+1+len(word) > w {
+				out = append(out, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+func Helper_spaces(n interface{}) string {
+	c := int(num(n))
+	if c < 0 {
+		c = 0
+	}
+	return strings.Repeat(" ", c)
+}
+
+func Helper_tabs(n interface{}) string {
+	c := int(num(n))
+	if c < 0 {
+		c = 0
+	}
+	return strings.Repeat("\t", c)
+}
+
+func Helper_trim(value string) string {
+	return strings.TrimSpace(value)
+}
+
+func Helper_quote(value interface{}) string {
+	return strconv.Quote(fmt.Sprint(value))
+}
+
+func Helper_squote(value interface{}) string {
+	return "'" + fmt.Sprint(value) + "'"
+}
+
+//
+// Pluralization, english only and rule based
+//
+
+var irregularPlurals = map[string]string{
+	"child":  "children",
+	"foot":   "feet",
+	"goose":  "geese",
+	"man":    "men",
+	"mouse":  "mice",
+	"ox":     "oxen",
+	"person": "people",
+	"tooth":  "teeth",
+	"woman":  "women",
+}
+
+var uncountables = map[string]struct{}{
+	"data":        struct{}{},
+	"equipment":   struct{}{},
+	"fish":        struct{}{},
+	"information": struct{}{},
+	"metadata":    struct{}{},
+	"money":       struct{}{},
+	"news":        struct{}{},
+	"series":      struct{}{},
+	"sheep":       struct{}{},
+	"species":     struct{}{},
+}
+
+func Helper_plural(word string) string {
+	lower := strings.ToLower(word)
+	if _, ok := uncountables[lower]; ok || word == "" {
+		return word
+	}
+	if p, ok := irregularPlurals[lower]; ok {
+		return matchCase(word, p)
+	}
+
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return word + "es"
+	case strings.HasSuffix(lower, "y") && !isVowel(lower, len(lower)-2):
+		return word[:len(word)-1] + "ies"
+	case strings.HasSuffix(lower, "fe"):
+		return word[:len(word)-2] + "ves"
+	case strings.HasSuffix(lower, "lf"):
+		return word[:len(word)-1] + "ves"
+	}
+	return word + "s"
+}
+
+func Helper_singular(word string) string {
+	lower := strings.ToLower(word)
+	if _, ok := uncountables[lower]; ok || word == "" {
+		return word
+	}
+	for s, p := range irregularPlurals {
+		if lower == p {
+			return matchCase(word, s)
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(lower, "ies"):
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "lves"):
+		return word[:len(word)-3] + "f"
+	case strings.HasSuffix(lower, "ves"):
+		return word[:len(word)-3] + "fe"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "zes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "ss"):
+		return word
+	case strings.HasSuffix(lower, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// Helper_pluralize returns the plural form unless count is one
+//   {{ .Count }} {{ pluralize .Count "file" }}
+func Helper_pluralize(count interface{}, word string) string {
+	if num(count) == 1 {
+		return word
+	}
+	return Helper_plural(word)
+}
+
+func isVowel(word string, pos int) bool {
+	if pos < 0 || pos >= len(word) {
+		return false
+	}
+	return strings.ContainsRune("aeiou", rune(word[pos]))
+}
+
+func matchCase(orig, word string) string {
+	if orig == strings.ToUpper(orig) && len(orig) > 1 {
+		return strings.ToUpper(word)
+	}
+	if unicode.IsUpper(rune(orig[0])) {
+		return strings.ToUpper(word[:1]) + word[1:]
+	}
+	return word
+}
@@ -0,0 +1,299 @@
+package templates
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aymerick/raymond"
+)
+
+// Helper_dict creates a map from key, value pairs
+//   {{ dict "name" .Name "type" "string" }}
+func Helper_dict(kvs ...interface{}) map[string]interface{} {
+	dict := make(map[string]interface{}, len(kvs)/2)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		dict[fmt.Sprint(kvs[i])] = kvs[i+1]
+	}
+	return dict
+}
+
+// Helper_dict_raymond creates a map from the hash arguments
+//   {{#with (dict name=Name type="string") }}
+func Helper_dict_raymond(options *raymond.Options) map[string]interface{} {
+	dict := make(map[string]interface{})
+	for k, v := range options.Hash() {
+		dict[k] = v
+	}
+	return dict
+}
+
+func Helper_list(elems ...interface{}) []interface{} {
+	list := make([]interface{}, 0, len(elems))
+	return append(list, elems...)
+}
+
+func Helper_keys(dict interface{}) []string {
+	val := reflect.ValueOf(dict)
+	if val.Kind() != reflect.Map {
+		return nil
+	}
+	keys := make([]string, 0, val.Len())
+	for _, k := range val.MapKeys() {
+		keys = append(keys, fmt.Sprint(k.Interface()))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Helper_values returns the values of a map, ordered by key
+func Helper_values(dict interface{}) []interface{} {
+	val := reflect.ValueOf(dict)
+	if val.Kind() != reflect.Map {
+		return nil
+	}
+	keys := val.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	vals := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		vals = append(vals, val.MapIndex(k).Interface())
+	}
+	return vals
+}
+
+func Helper_haskey(dict interface{}, key string) interface{} {
+	if _, ok := mapLookup(dict, key); ok {
+		return true
+	}
+	return nil
+}
+
+func Helper_get(dict interface{}, key string) interface{} {
+	v, _ := mapLookup(dict, key)
+	return v
+}
+
+// Helper_put returns a copy of the map with key set to value
+func Helper_put(dict interface{}, key string, value interface{}) map[string]interface{} {
+	ret := toDict(dict)
+	ret[key] = value
+	return ret
+}
+
+// Helper_pick returns a copy of the map with only the listed keys, (a list or a comma separated string)
+func Helper_pick(dict interface{}, keys interface{}) map[string]interface{} {
+	ret := map[string]interface{}{}
+	for _, k := range toStringList(keys) {
+		if v, ok := mapLookup(dict, k); ok {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+// Helper_omit returns a copy of the map without the listed keys, (a list or a comma separated string)
+func Helper_omit(dict interface{}, keys interface{}) map[string]interface{} {
+	ret := toDict(dict)
+	for _, k := range toStringList(keys) {
+		delete(ret, k)
+	}
+	return ret
+}
+
+// Helper_merge returns a new map, values in the second take precedence
+func Helper_merge(lhs, rhs interface{}) map[string]interface{} {
+	ret := toDict(lhs)
+	for k, v := range toDict(rhs) {
+		ret[k] = v
+	}
+	return ret
+}
+
+func Helper_append(list interface{}, elem interface{}) []interface{} {
+	return append(toList(list), elem)
+}
+
+func Helper_prepend(list interface{}, elem interface{}) []interface{} {
+	return append([]interface{}{elem}, toList(list)...)
+}
+
+func Helper_first(list interface{}) interface{} {
+	l := toList(list)
+	if len(l) == 0 {
+		return nil
+	}
+	return l[0]
+}
+
+func Helper_last(list interface{}) interface{} {
+	l := toList(list)
+	if len(l) == 0 {
+		return nil
+	}
+	return l[len(l)-1]
+}
+
+func Helper_rest(list interface{}) []interface{} {
+	l := toList(list)
+	if len(l) == 0 {
+		return l
+	}
+	return l[1:]
+}
+
+func Helper_has(list interface{}, elem interface{}) interface{} {
+	for _, e := range toList(list) {
+		if reflect.DeepEqual(e, elem) {
+			return true
+		}
+	}
+	return nil
+}
+
+// Helper_uniq removes duplicates, keeping the first occurance
+func Helper_uniq(list interface{}) []interface{} {
+	ret := []interface{}{}
+	for _, e := range toList(list) {
+		if Helper_has(ret, e) == nil {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// Helper_union is the set of elements in either list
+func Helper_union(lhs, rhs interface{}) []interface{} {
+	return Helper_uniq(append(toList(lhs), toList(rhs)...))
+}
+
+// Helper_intersect is the set of elements in both lists
+func Helper_intersect(lhs, rhs interface{}) []interface{} {
+	ret := []interface{}{}
+	for _, e := range Helper_uniq(lhs) {
+		if Helper_has(rhs, e) != nil {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// Helper_difference is the set of elements in the first, but not the second list
+func Helper_difference(lhs, rhs interface{}) []interface{} {
+	ret := []interface{}{}
+	for _, e := range Helper_uniq(lhs) {
+		if Helper_has(rhs, e) == nil {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+func Helper_sortstr(list interface{}) []string {
+	ret := toStringList(list)
+	sort.Strings(ret)
+	return ret
+}
+
+// Helper_sortby sorts a list of maps by the value at a key
+//   {{ range sortby .Fields "Name" }}
+func Helper_sortby(list interface{}, key string) []interface{} {
+	return sortByKey(list, key, false)
+}
+
+func Helper_rsortby(list interface{}, key string) []interface{} {
+	return sortByKey(list, key, true)
+}
+
+// Helper_pluck extracts the value at key from each map in the list
+func Helper_pluck(list interface{}, key string) []interface{} {
+	ret := []interface{}{}
+	for _, e := range toList(list) {
+		if v, ok := mapLookup(e, key); ok {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func sortByKey(list interface{}, key string, reverse bool) []interface{} {
+	ret := toList(list)
+	sort.SliceStable(ret, func(i, j int) bool {
+		lv, _ := mapLookup(ret[i], key)
+		rv, _ := mapLookup(ret[j], key)
+		if reverse {
+			return lessValue(rv, lv)
+		}
+		return lessValue(lv, rv)
+	})
+	return ret
+}
+
+// numbers compare numerically, everything else as strings
+func lessValue(lhs, rhs interface{}) bool {
+	lf, lok := toFloat(lhs)
+	rf, rok := toFloat(rhs)
+	if lok && rok {
+		return lf < rf
+	}
+	return fmt.Sprint(lhs) < fmt.Sprint(rhs)
+}
+
+func mapLookup(dict interface{}, key string) (interface{}, bool) {
+	val := reflect.ValueOf(dict)
+	if val.Kind() != reflect.Map || val.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	v := val.MapIndex(reflect.ValueOf(key).Convert(val.Type().Key()))
+	if !v.IsValid() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// toDict makes a shallow copy of any map with string keys
+func toDict(dict interface{}) map[string]interface{} {
+	ret := map[string]interface{}{}
+	val := reflect.ValueOf(dict)
+	if val.Kind() != reflect.Map {
+		return ret
+	}
+	iter := val.MapRange()
+	for iter.Next() {
+		ret[fmt.Sprint(iter.Key().Interface())] = iter.Value().Interface()
+	}
+	return ret
+}
+
+// toList makes a shallow copy of any slice or array
+func toList(list interface{}) []interface{} {
+	val := reflect.ValueOf(list)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return []interface{}{}
+	}
+	ret := make([]interface{}, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		ret = append(ret, val.Index(i).Interface())
+	}
+	return ret
+}
+
+// toStringList accepts a list or a comma separated string
+func toStringList(list interface{}) []string {
+	if s, ok := list.(string); ok {
+		ret := []string{}
+		for _, e := range strings.Split(s, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				ret = append(ret, e)
+			}
+		}
+		return ret
+	}
+	ret := []string{}
+	for _, e := range toList(list) {
+		ret = append(ret, fmt.Sprint(e))
+	}
+	return ret
+}
@@ -0,0 +1,73 @@
+package templates
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+func Helper_md5(value string) string {
+	sum := md5.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func Helper_sha1(value string) string {
+	sum := sha1.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func Helper_sha256(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func Helper_sha512(value string) string {
+	sum := sha512.Sum512([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// Helper_uuid returns a new random uuid, prefer uuid5 for stable output
+func Helper_uuid() string {
+	return uuid.New().String()
+}
+
+// Helper_uuid5 returns a stable uuid for a name within a namespace,
+// which is one of "dns", "url", "oid", "x500", or a uuid
+func Helper_uuid5(namespace, name string) string {
+	var ns uuid.UUID
+	switch namespace {
+	case "dns":
+		ns = uuid.NameSpaceDNS
+	case "url":
+		ns = uuid.NameSpaceURL
+	case "oid":
+		ns = uuid.NameSpaceOID
+	case "x500":
+		ns = uuid.NameSpaceX500
+	default:
+		var err error
+		ns, err = uuid.Parse(namespace)
+		if err != nil {
+			return fmt.Sprintf("ERROR: %v", err)
+		}
+	}
+	return uuid.NewSHA1(ns, []byte(name)).String()
+}
+
+func Helper_b64enc(value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+func Helper_b64dec(value string) string {
+	bytes, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return fmt.Sprintf("ERROR: %v", err)
+	}
+	return string(bytes)
+}
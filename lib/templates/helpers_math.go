@@ -0,0 +1,145 @@
+package templates
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// The math helpers accept any numeric type (or numeric string),
+// because data decoded from Cue is usually float64 and template
+// literals are int. Integral results are returned as int.
+
+func Helper_add(lhs, rhs interface{}) interface{} {
+	return numResult(num(lhs) + num(rhs))
+}
+
+func Helper_inc(val interface{}) interface{} {
+	return numResult(num(val) + 1)
+}
+
+func Helper_sub(lhs, rhs interface{}) interface{} {
+	return numResult(num(lhs) - num(rhs))
+}
+
+func Helper_mul(lhs, rhs interface{}) interface{} {
+	return numResult(num(lhs) * num(rhs))
+}
+
+func Helper_div(lhs, rhs interface{}) interface{} {
+	d := num(rhs)
+	if d == 0 {
+		return "ERROR: division by zero"
+	}
+	return numResult(num(lhs) / d)
+}
+
+func Helper_mod(lhs, rhs interface{}) interface{} {
+	d := int(num(rhs))
+	if d == 0 {
+		return "ERROR: division by zero"
+	}
+	return int(num(lhs)) % d
+}
+
+func Helper_dec(val interface{}) interface{} {
+	return numResult(num(val) - 1)
+}
+
+// Helper_max returns the largest number in a list
+func Helper_max(list interface{}) interface{} {
+	l := toList(list)
+	if len(l) == 0 {
+		return nil
+	}
+	m := num(l[0])
+	for _, e := range l[1:] {
+		m = math.Max(m, num(e))
+	}
+	return numResult(m)
+}
+
+// Helper_min returns the smallest number in a list
+func Helper_min(list interface{}) interface{} {
+	l := toList(list)
+	if len(l) == 0 {
+		return nil
+	}
+	m := num(l[0])
+	for _, e := range l[1:] {
+		m = math.Min(m, num(e))
+	}
+	return numResult(m)
+}
+
+func Helper_sum(list interface{}) interface{} {
+	s := 0.0
+	for _, e := range toList(list) {
+		s += num(e)
+	}
+	return numResult(s)
+}
+
+func Helper_floor(val interface{}) int {
+	return int(math.Floor(num(val)))
+}
+
+func Helper_ceil(val interface{}) int {
+	return int(math.Ceil(num(val)))
+}
+
+func Helper_round(val interface{}) int {
+	return int(math.Round(num(val)))
+}
+
+func Helper_int(val interface{}) int {
+	return int(num(val))
+}
+
+func Helper_float(val interface{}) float64 {
+	return num(val)
+}
+
+// Helper_seq returns the list [start, end), useful for range
+func Helper_seq(start, end interface{}) []int {
+	ret := []int{}
+	for i := int(num(start)); i < int(num(end)); i++ {
+		ret = append(ret, i)
+	}
+	return ret
+}
+
+func num(val interface{}) float64 {
+	f, _ := toFloat(val)
+	return f
+}
+
+func numResult(f float64) interface{} {
+	if f == math.Trunc(f) && math.Abs(f) < math.MaxInt32 {
+		return int(f)
+	}
+	return f
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
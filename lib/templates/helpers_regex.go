@@ -0,0 +1,50 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+)
+
+func Helper_regex_match(pattern, str string) interface{} {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+	if re.MatchString(str) {
+		return true
+	}
+	return nil
+}
+
+func Helper_regex_find(pattern, str string) string {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Sprintf("ERROR: %v", err)
+	}
+	return re.FindString(str)
+}
+
+func Helper_regex_findall(pattern, str string) []string {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return []string{fmt.Sprintf("ERROR: %v", err)}
+	}
+	return re.FindAllString(str, -1)
+}
+
+// Helper_regex_replace supports $1 style expansion in the replacement
+func Helper_regex_replace(pattern, str, repl string) string {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Sprintf("ERROR: %v", err)
+	}
+	return re.ReplaceAllString(str, repl)
+}
+
+func Helper_regex_split(pattern, str string) []string {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return []string{fmt.Sprintf("ERROR: %v", err)}
+	}
+	return re.Split(str, -1)
+}
@@ -0,0 +1,312 @@
+package templates_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/lib/templates"
+)
+
+type helperCase struct {
+	// template source for each system, empty skips that system
+	Golang  string
+	Raymond string
+	Expect  string
+}
+
+var helperData = map[string]interface{}{
+	"Name":  "user",
+	"Count": 3.0,
+	"Tags":  []interface{}{"b", "a", "c", "a"},
+	"Other": []interface{}{"c", "d"},
+	"Nums":  []interface{}{3.0, 1.5, 7.0},
+	"Map":   map[string]interface{}{"a": 1, "b": 2, "c": 3},
+	"Fields": []interface{}{
+		map[string]interface{}{"Name": "zeta", "Order": 2.0},
+		map[string]interface{}{"Name": "alpha", "Order": 10.0},
+		map[string]interface{}{"Name": "beta", "Order": 1.0},
+	},
+	"Text":  "a\nb",
+	"Lines": "    a\n      b\n    c",
+	"Code":  "func main() {\n\tfmt.Println()\n}",
+}
+
+var helperCases = map[string][]helperCase{
+	"dict": {
+		{`{{ with dict "a" 1 "b" .Name }}{{ .a }}-{{ .b }}{{ end }}`, `{{#with (dict a=1 b=Name)}}{{a}}-{{b}}{{/with}}`, "1-user"},
+	},
+	"list": {
+		{`{{ range list "x" "y" }}{{ . }}{{ end }}`, `{{#each (list a="x" b="y")}}{{this}}{{/each}}`, "xy"},
+		// raymond variadic arguments are sorted by key, not as written
+		{``, `{{#each (list z="x" a="y")}}{{this}}{{/each}}`, "yx"},
+	},
+	"keys": {
+		{`{{ range keys .Map }}{{ . }}{{ end }}`, `{{#each (keys Map)}}{{this}}{{/each}}`, "abc"},
+	},
+	"values": {
+		{`{{ range values .Map }}{{ . }}{{ end }}`, `{{#each (values Map)}}{{this}}{{/each}}`, "123"},
+	},
+	"haskey": {
+		{`{{ if haskey .Map "a" }}yes{{ end }}{{ if haskey .Map "z" }}no{{ end }}`, `{{#if (haskey Map "a")}}yes{{/if}}{{#if (haskey Map "z")}}no{{/if}}`, "yes"},
+	},
+	"get": {
+		{`{{ get .Map "b" }}`, `{{get Map "b"}}`, "2"},
+	},
+	"put": {
+		{`{{ get (put .Map "d" 4) "d" }}`, `{{get (put Map "d" 4) "d"}}`, "4"},
+	},
+	"pick": {
+		{`{{ range values (pick .Map "a,c") }}{{ . }}{{ end }}`, `{{#each (values (pick Map "a,c"))}}{{this}}{{/each}}`, "13"},
+	},
+	"omit": {
+		{`{{ range values (omit .Map "a,c") }}{{ . }}{{ end }}`, `{{#each (values (omit Map "a,c"))}}{{this}}{{/each}}`, "2"},
+	},
+	"merge": {
+		{`{{ range values (merge .Map (dict "a" 9)) }}{{ . }}{{ end }}`, `{{#each (values (merge Map (dict a=9)))}}{{this}}{{/each}}`, "923"},
+	},
+	"append": {
+		{`{{ range append .Other "e" }}{{ . }}{{ end }}`, `{{#each (append Other "e")}}{{this}}{{/each}}`, "cde"},
+	},
+	"prepend": {
+		{`{{ range prepend .Other "e" }}{{ . }}{{ end }}`, `{{#each (prepend Other "e")}}{{this}}{{/each}}`, "ecd"},
+	},
+	"first": {
+		{`{{ first .Tags }}`, `{{first Tags}}`, "b"},
+	},
+	"last": {
+		{`{{ last .Tags }}`, `{{last Tags}}`, "a"},
+	},
+	"rest": {
+		{`{{ range rest .Other }}{{ . }}{{ end }}`, `{{#each (rest Other)}}{{this}}{{/each}}`, "d"},
+	},
+	"has": {
+		{`{{ if has .Tags "c" }}yes{{ end }}`, `{{#if (has Tags "c")}}yes{{/if}}`, "yes"},
+	},
+	"uniq": {
+		{`{{ range uniq .Tags }}{{ . }}{{ end }}`, `{{#each (uniq Tags)}}{{this}}{{/each}}`, "bac"},
+	},
+	"union": {
+		{`{{ range union .Tags .Other }}{{ . }}{{ end }}`, `{{#each (union Tags Other)}}{{this}}{{/each}}`, "bacd"},
+	},
+	"intersect": {
+		{`{{ range intersect .Tags .Other }}{{ . }}{{ end }}`, `{{#each (intersect Tags Other)}}{{this}}{{/each}}`, "c"},
+	},
+	"difference": {
+		{`{{ range difference .Tags .Other }}{{ . }}{{ end }}`, `{{#each (difference Tags Other)}}{{this}}{{/each}}`, "ba"},
+	},
+	"sortstr": {
+		{`{{ range sortstr .Tags }}{{ . }}{{ end }}`, `{{#each (sortstr Tags)}}{{this}}{{/each}}`, "aabc"},
+	},
+	"sortby": {
+		{`{{ range sortby .Fields "Name" }}{{ .Name }} {{ end }}`, `{{#each (sortby Fields "Name")}}{{Name}} {{/each}}`, "alpha beta zeta "},
+		{`{{ range sortby .Fields "Order" }}{{ .Name }} {{ end }}`, `{{#each (sortby Fields "Order")}}{{Name}} {{/each}}`, "beta zeta alpha "},
+	},
+	"rsortby": {
+		{`{{ range rsortby .Fields "Name" }}{{ .Name }} {{ end }}`, `{{#each (rsortby Fields "Name")}}{{Name}} {{/each}}`, "zeta beta alpha "},
+	},
+	"pluck": {
+		{`{{ range pluck .Fields "Name" }}{{ . }} {{ end }}`, `{{#each (pluck Fields "Name")}}{{this}} {{/each}}`, "zeta alpha beta "},
+	},
+
+	"add": {
+		{`{{ add .Count 1 }}`, `{{add Count 1}}`, "4"},
+		{`{{ add .Count 0.5 }}`, `{{add Count 0.5}}`, "3.5"},
+	},
+	"inc": {
+		{`{{ inc .Count }}`, `{{inc Count}}`, "4"},
+		{`{{ inc "1" }}`, `{{inc "1"}}`, "2"},
+	},
+	"sub": {
+		{`{{ sub .Count 1 }}`, `{{sub Count 1}}`, "2"},
+	},
+	"mul": {
+		{`{{ mul .Count 1.5 }}`, `{{mul Count 1.5}}`, "4.5"},
+	},
+	"div": {
+		{`{{ div .Count 2 }}`, `{{div Count 2}}`, "1.5"},
+		{`{{ div .Count 0 }}`, `{{div Count 0}}`, "ERROR: division by zero"},
+	},
+	"mod": {
+		{`{{ mod 7 .Count }}`, `{{mod 7 Count}}`, "1"},
+	},
+	"dec": {
+		{`{{ dec .Count }}`, `{{dec Count}}`, "2"},
+	},
+	"max": {
+		{`{{ max .Nums }}`, `{{max Nums}}`, "7"},
+	},
+	"min": {
+		{`{{ min .Nums }}`, `{{min Nums}}`, "1.5"},
+	},
+	"sum": {
+		{`{{ sum .Nums }}`, `{{sum Nums}}`, "11.5"},
+	},
+	"floor": {
+		{`{{ floor 1.7 }}`, `{{floor 1.7}}`, "1"},
+	},
+	"ceil": {
+		{`{{ ceil 1.2 }}`, `{{ceil 1.2}}`, "2"},
+	},
+	"round": {
+		{`{{ round 1.5 }}`, `{{round 1.5}}`, "2"},
+	},
+	"int": {
+		{`{{ int "42" }}`, `{{int "42"}}`, "42"},
+	},
+	"float": {
+		{`{{ float "4.25" }}`, `{{float "4.25"}}`, "4.25"},
+	},
+	"seq": {
+		{`{{ range seq 0 .Count }}{{ . }}{{ end }}`, `{{#each (seq 0 Count)}}{{this}}{{/each}}`, "012"},
+	},
+
+	"regex_match": {
+		{`{{ if regex_match "^u.e" .Name }}yes{{ end }}`, `{{#if (regex_match "^u.e" Name)}}yes{{/if}}`, "yes"},
+	},
+	"regex_find": {
+		{`{{ regex_find "[0-9]+" "abc123def45" }}`, `{{regex_find "[0-9]+" "abc123def45"}}`, "123"},
+	},
+	"regex_findall": {
+		{`{{ range regex_findall "[0-9]+" "abc123def45" }}{{ . }},{{ end }}`, `{{#each (regex_findall "[0-9]+" "abc123def45")}}{{this}},{{/each}}`, "123,45,"},
+	},
+	"regex_replace": {
+		{`{{ regex_replace "(\\w+)@(\\w+)" "me@host" "$2:$1" }}`, `{{regex_replace "(\w+)@(\w+)" "me@host" "$2:$1"}}`, "host:me"},
+	},
+	"regex_split": {
+		{`{{ range regex_split "[,;]" "a,b;c" }}{{ . }}{{ end }}`, `{{#each (regex_split "[,;]" "a,b;c")}}{{this}}{{/each}}`, "abc"},
+	},
+
+	"now": {
+		{`{{ if now }}ok{{ end }}`, `{{#if (now)}}ok{{/if}}`, "ok"},
+	},
+	"date": {
+		{`{{ date "2006-01-02" "2020-07-04T10:00:00Z" }}`, `{{date "2006-01-02" "2020-07-04T10:00:00Z"}}`, "2020-07-04"},
+		{`{{ date "2006" 0 }}`, `{{date "2006" 0}}`, "1970"},
+	},
+	"date_parse": {
+		{`{{ date "Jan 2" (date_parse "2006-01-02" "2020-07-04") }}`, `{{date "Jan 2" (date_parse "2006-01-02" "2020-07-04")}}`, "Jul 4"},
+	},
+	"date_add": {
+		{`{{ date "2006-01-02" (date_add "2020-07-04T10:00:00Z" "24h") }}`, `{{date "2006-01-02" (date_add "2020-07-04T10:00:00Z" "24h")}}`, "2020-07-05"},
+	},
+	"unix": {
+		{`{{ unix "1970-01-01T00:01:00Z" }}`, `{{unix "1970-01-01T00:01:00Z"}}`, "60"},
+	},
+
+	"md5": {
+		{`{{ md5 "hof" }}`, `{{md5 "hof"}}`, "64a04398c02c80353502d35f32f12e78"},
+	},
+	"sha1": {
+		{`{{ sha1 "hof" }}`, `{{sha1 "hof"}}`, "660a900e4bff52834cd2f876095dbc6036c8646d"},
+	},
+	"sha256": {
+		{`{{ sha256 "hof" }}`, `{{sha256 "hof"}}`, "639097e715e88be050d0e42a964d2c78d9d938d48cb71630f0633e4c8fa35cce"},
+	},
+	"sha512": {
+		{`{{ sha512 "hof" }}`, `{{sha512 "hof"}}`, "3ddf2bf548a945c15cd8c465a9575b45d95c3ef13d334366b0e8ce4822f281012901fcf4874a957af66a04cf6a19994e10d7bf8eff0a7da642bdcc5c0d825261"},
+	},
+	"uuid": {
+		{`{{ if regex_match "^[0-9a-f-]{36}$" uuid }}ok{{ end }}`, `{{#if (regex_match "^[0-9a-f-]{36}$" (uuid))}}ok{{/if}}`, "ok"},
+	},
+	"uuid5": {
+		{`{{ uuid5 "dns" "hofstadter.io" }}`, `{{uuid5 "dns" "hofstadter.io"}}`, "5ac578c8-957c-5c2c-8517-fb1afcfb5bbc"},
+	},
+	"b64enc": {
+		{`{{ b64enc "hof gen" }}`, `{{b64enc "hof gen"}}`, "aG9mIGdlbg=="},
+	},
+	"b64dec": {
+		{`{{ b64dec "aG9mIGdlbg==" }}`, `{{b64dec "aG9mIGdlbg=="}}`, "hof gen"},
+	},
+	"jsoninline": {
+		{`{{ jsoninline .Other }}`, `{{{jsoninline Other}}}`, `["c","d"]`},
+	},
+
+	"plural": {
+		{`{{ plural "user" }} {{ plural "Class" }} {{ plural "policy" }} {{ plural "key" }} {{ plural "person" }} {{ plural "data" }}`,
+			`{{plural "user"}} {{plural "Class"}} {{plural "policy"}} {{plural "key"}} {{plural "person"}} {{plural "data"}}`,
+			"users Classes policies keys people data"},
+	},
+	"singular": {
+		{`{{ singular "users" }} {{ singular "classes" }} {{ singular "policies" }} {{ singular "People" }} {{ singular "address" }}`,
+			`{{singular "users"}} {{singular "classes"}} {{singular "policies"}} {{singular "People"}} {{singular "address"}}`,
+			"user class policy Person address"},
+	},
+	"pluralize": {
+		{`{{ pluralize .Count "file" }} {{ pluralize 1 "file" }}`, `{{pluralize Count "file"}} {{pluralize 1 "file"}}`, "files file"},
+	},
+
+	"indentn": {
+		{`{{ indentn .Text 2 }}`, `{{indentn Text 2}}`, "  a\n  b"},
+	},
+	"nindent": {
+		{`x:{{ nindent "a" "  " }}`, `x:{{nindent "a" "  "}}`, "x:\n  a"},
+	},
+	"dedent": {
+		{`{{ dedent .Lines }}`, `{{dedent Lines}}`, "a\n  b\nc"},
+	},
+	"comment": {
+		{`{{ comment .Code "// " }}`, `{{{comment Code "// "}}}`, "// func main() {\n// \tfmt.Println()\n// }"},
+	},
+	"wrap": {
+		{`{{ wrap "the quick brown fox" 10 }}`, `{{wrap "the quick brown fox" 10}}`, "the quick\nbrown fox"},
+	},
+	"spaces": {
+		{`[{{ spaces 3 }}]`, `[{{spaces 3}}]`, "[   ]"},
+	},
+	"tabs": {
+		{`[{{ tabs 2 }}]`, `[{{tabs 2}}]`, "[\t\t]"},
+	},
+	"trim": {
+		{`[{{ trim "  a b  " }}]`, `[{{trim "  a b  "}}]`, "[a b]"},
+	},
+	"quote": {
+		{`{{ quote .Name }}`, `{{{quote Name}}}`, `"user"`},
+	},
+	"squote": {
+		{`{{ squote .Name }}`, `{{{squote Name}}}`, `'user'`},
+	},
+	"printf": {
+		{`{{ printf "%s-%v" .Name .Count }}`, `{{printf "%s-%v" a=Name b=Count}}`, "user-3"},
+	},
+}
+
+func renderHelper(system, name, source string) (string, error) {
+	config := &templates.Config{
+		TemplateSystem: system,
+		LHS2_D:         "{{",
+		RHS2_D:         "}}",
+		LHS3_D:         "{{{",
+		RHS3_D:         "}}}",
+	}
+
+	T, err := templates.CreateFromString(name, source, system, config)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := T.Render(helperData)
+	return string(out), err
+}
+
+func TestHelpers(t *testing.T) {
+	for name, cases := range helperCases {
+		for i, c := range cases {
+			for _, system := range []string{"golang", "raymond"} {
+				src := c.Golang
+				if system == "raymond" {
+					src = c.Raymond
+				}
+				if src == "" {
+					continue
+				}
+
+				msg := fmt.Sprintf("%s[%d] %s: %s", name, i, system, src)
+				out, err := renderHelper(system, name, src)
+				assert.Nil(t, err, msg)
+				assert.Equal(t, c.Expect, out, msg)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package templates
+
+import (
+	"fmt"
+	"time"
+)
+
+func Helper_now() time.Time {
+	return time.Now()
+}
+
+// Helper_date formats a time, RFC3339 string, or unix seconds with a golang layout
+//   {{ date "2006-01-02" now }}
+func Helper_date(layout string, t interface{}) string {
+	tm, err := toTime(t)
+	if err != nil {
+		return fmt.Sprintf("ERROR: %v", err)
+	}
+	return tm.Format(layout)
+}
+
+func Helper_date_parse(layout, value string) interface{} {
+	tm, err := time.Parse(layout, value)
+	if err != nil {
+		return fmt.Sprintf("ERROR: %v", err)
+	}
+	return tm
+}
+
+// Helper_date_add adds a golang duration string, like "24h" or "-90m"
+func Helper_date_add(t interface{}, duration string) interface{} {
+	tm, err := toTime(t)
+	if err != nil {
+		return fmt.Sprintf("ERROR: %v", err)
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return fmt.Sprintf("ERROR: %v", err)
+	}
+	return tm.Add(d)
+}
+
+func Helper_unix(t interface{}) interface{} {
+	tm, err := toTime(t)
+	if err != nil {
+		return fmt.Sprintf("ERROR: %v", err)
+	}
+	return tm.Unix()
+}
+
+func toTime(t interface{}) (time.Time, error) {
+	switch v := t.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		return *v, nil
+	case string:
+		return time.Parse(time.RFC3339, v)
+	}
+	if f, ok := toFloat(t); ok {
+		return time.Unix(int64(f), 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown time value %v", t)
+}
@@ -0,0 +1,129 @@
+package templates
+
+import (
+	"fmt"
+	"text/template/parse"
+
+	"github.com/aymerick/raymond"
+)
+
+// Layouts are base templates with overridable blocks.
+//
+// golang:  the layout uses {{ block "name" . }}default{{ end }}
+//          and the file overrides with {{ define "name" }}...{{ end }}
+// raymond: the layout uses {{#block "name"}}default{{/block}}
+//          and the file overrides with {{#define "name"}}...{{/define}}
+//
+// Anything the file renders outside of a define fills the "content" block.
+
+// A LayoutSystem is a TemplateSystem which supports layouts
+type LayoutSystem interface {
+	// Extend returns a new template which renders child into layout
+	Extend(layout, child *Template) (*Template, error)
+}
+
+const LayoutContentBlock = "content"
+
+// ApplyLayout returns a template which renders T within the layout L
+func ApplyLayout(L, T *Template) (*Template, error) {
+	if L.System != T.System {
+		return nil, fmt.Errorf("layout %q and template %q must use the same template system", L.Name, T.Name)
+	}
+
+	S, ok := T.System.(LayoutSystem)
+	if !ok {
+		return nil, fmt.Errorf("template system for %q does not support layouts", T.Name)
+	}
+
+	return S.Extend(L, T)
+}
+
+func (S *golangSystem) Extend(layout, child *Template) (*Template, error) {
+	t, err := layout.T.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	// redefine the layout blocks with the child's templates
+	for _, c := range child.T.Templates() {
+		if c.Tree == nil {
+			continue
+		}
+		name := c.Name()
+		if name == child.T.Name() {
+			if parse.IsEmptyTree(c.Tree.Root) {
+				continue
+			}
+			name = LayoutContentBlock
+		}
+		_, err := t.AddParseTree(name, c.Tree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Template{
+		Name:   child.Name,
+		Source: child.Source,
+		Config: child.Config,
+		System: S,
+		T:      t,
+	}, nil
+}
+
+type raymondLayout struct {
+	layout *raymond.Template
+}
+
+func (S *raymondSystem) Extend(layout, child *Template) (*Template, error) {
+	return &Template{
+		Name:   child.Name,
+		Source: child.Source,
+		Config: child.Config,
+		System: S,
+		R:      child.R,
+		Impl:   &raymondLayout{layout: layout.R},
+	}, nil
+}
+
+// The child is rendered first, collecting its defines,
+// then the layout is rendered with them available to its blocks
+func (L *raymondLayout) exec(child *raymond.Template, data interface{}) (string, error) {
+	blocks := map[string]string{}
+	df := raymond.NewDataFrame()
+	df.Set(raymondBlocksKey, blocks)
+
+	content, err := child.ExecWith(data, df)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := blocks[LayoutContentBlock]; !ok {
+		blocks[LayoutContentBlock] = content
+	}
+
+	return L.layout.ExecWith(data, df)
+}
+
+const raymondBlocksKey = "hofLayoutBlocks"
+
+// Raymond only helpers for layouts
+var raymondLayoutHelpers = map[string]interface{}{
+	"block":  Helper_block_raymond,
+	"define": Helper_define_raymond,
+}
+
+func Helper_block_raymond(name string, options *raymond.Options) raymond.SafeString {
+	if blocks, ok := options.Data(raymondBlocksKey).(map[string]string); ok {
+		if content, ok := blocks[name]; ok {
+			return raymond.SafeString(content)
+		}
+	}
+	return raymond.SafeString(options.Fn())
+}
+
+func Helper_define_raymond(name string, options *raymond.Options) raymond.SafeString {
+	if blocks, ok := options.Data(raymondBlocksKey).(map[string]string); ok {
+		blocks[name] = options.Fn()
+	}
+	return ""
+}
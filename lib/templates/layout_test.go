@@ -0,0 +1,86 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/hofstadter-io/hof/lib/templates"
+)
+
+type layoutCase struct {
+	Layout string
+	Child  string
+	Expect string
+}
+
+var layoutCases = map[string][]layoutCase{
+	"golang": {
+		{
+			Layout: `// {{ block "header" . }}default header{{ end }}` + "\n" + `{{ block "content" . }}{{ end }}// {{ .Name }} footer`,
+			Child:  `{{ define "header" }}custom header{{ end }}body of {{ .Name }}` + "\n",
+			Expect: "// custom header\nbody of hof\n// hof footer",
+		},
+		{
+			Layout: `[{{ block "header" . }}default{{ end }}]`,
+			Child:  `{{ define "unused" }}x{{ end }}`,
+			Expect: "[default]",
+		},
+	},
+	"raymond": {
+		{
+			Layout: `// {{#block "header"}}default header{{/block}}` + "\n" + `{{#block "content"}}{{/block}}// {{ Name }} footer`,
+			Child:  `{{#define "header"}}custom header{{/define}}body of {{ Name }}` + "\n",
+			Expect: "// custom header\nbody of hof\n// hof footer",
+		},
+		{
+			Layout: `[{{#block "header"}}default{{/block}}]`,
+			Child:  `{{#define "unused"}}x{{/define}}`,
+			Expect: "[default]",
+		},
+	},
+}
+
+func TestLayouts(t *testing.T) {
+	data := map[string]interface{}{"Name": "hof"}
+
+	for system, cases := range layoutCases {
+		config := &templates.Config{
+			TemplateSystem: system,
+			LHS2_D:         "{{",
+			RHS2_D:         "}}",
+			LHS3_D:         "{{{",
+			RHS3_D:         "}}}",
+		}
+
+		for i, c := range cases {
+			L, err := templates.CreateFromString("layout", c.Layout, system, config)
+			if err != nil {
+				t.Fatalf("%s[%d] layout: %v", system, i, err)
+			}
+			C, err := templates.CreateFromString("child", c.Child, system, config)
+			if err != nil {
+				t.Fatalf("%s[%d] child: %v", system, i, err)
+			}
+
+			T, err := templates.ApplyLayout(L, C)
+			if err != nil {
+				t.Fatalf("%s[%d] apply: %v", system, i, err)
+			}
+
+			// render twice, layouts are reused across files
+			for n := 0; n < 2; n++ {
+				out, err := T.Render(data)
+				if err != nil {
+					t.Fatalf("%s[%d] render: %v", system, i, err)
+				}
+				if string(out) != c.Expect {
+					t.Errorf("%s[%d] expected\n%q\ngot\n%q", system, i, c.Expect, string(out))
+				}
+			}
+
+			// the layout itself renders its defaults
+			if _, err := L.Render(data); err != nil {
+				t.Fatalf("%s[%d] layout render: %v", system, i, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package templates
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// A TemplateSystem turns template source into output.
+// Register new systems with RegisterTemplateSystem,
+// the name is what users set as TemplateSystem in Cue.
+type TemplateSystem interface {
+	// Create parses T.Source, using T.Config, and stores
+	// the result in T.T, T.R, or T.Impl for use by Render
+	Create(T *Template) error
+
+	// Render executes a template created by this system
+	Render(T *Template, data interface{}) ([]byte, error)
+}
+
+var (
+	systemsMutex sync.RWMutex
+	systems      = map[string]TemplateSystem{}
+)
+
+func init() {
+	RegisterTemplateSystem("golang", &golangSystem{})
+	RegisterTemplateSystem("raymond", &raymondSystem{})
+	RegisterTemplateSystem("data", &dataSystem{})
+}
+
+// RegisterTemplateSystem makes a template system available by name, it panics on duplicates
+func RegisterTemplateSystem(name string, S TemplateSystem) {
+	systemsMutex.Lock()
+	defer systemsMutex.Unlock()
+
+	if _, ok := systems[name]; ok {
+		panic(fmt.Sprintf("template system %q already registered", name))
+	}
+	systems[name] = S
+}
+
+func LookupTemplateSystem(name string) (TemplateSystem, bool) {
+	systemsMutex.RLock()
+	defer systemsMutex.RUnlock()
+
+	S, ok := systems[name]
+	return S, ok
+}
+
+func KnownTemplateSystems() []string {
+	systemsMutex.RLock()
+	defer systemsMutex.RUnlock()
+
+	names := make([]string, 0, len(systems))
+	for name, _ := range systems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
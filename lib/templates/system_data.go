@@ -0,0 +1,178 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/format"
+	cueyaml "cuelang.org/go/encoding/yaml"
+	"github.com/clbanning/mxj"
+	"github.com/ghodss/yaml"
+	"github.com/naoina/toml"
+)
+
+// The data system has no template text,
+// the source is the output format and the value is serialized directly.
+// An empty source infers the format from the template name's extension.
+//
+// Given a cue.Value, json, yaml, and cue keep Cue's field order.
+// toml and xml, and any decoded Go value, have their keys sorted.
+type dataSystem struct{}
+
+type dataEncoder func(data interface{}) ([]byte, error)
+
+type dataValueEncoder func(V cue.Value) ([]byte, error)
+
+var dataEncoders = map[string]dataEncoder{
+	"json": encodeJSON,
+	"yaml": yaml.Marshal,
+	"yml":  yaml.Marshal,
+	"toml": toml.Marshal,
+	"cue":  encodeCUE,
+	"xml":  encodeXML,
+}
+
+var dataValueEncoders = map[string]dataValueEncoder{
+	"json": encodeValueJSON,
+	"yaml": cueyaml.Encode,
+	"yml":  cueyaml.Encode,
+	"cue":  encodeValueCUE,
+}
+
+func (S *dataSystem) Create(T *Template) error {
+	format := strings.TrimSpace(T.Source)
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(T.Name), ".")
+	}
+
+	if _, ok := dataEncoders[format]; !ok {
+		return fmt.Errorf("Unknown data format %q for file %q, should be one of json, yaml, toml, cue, xml", format, T.Name)
+	}
+
+	T.Impl = format
+	return nil
+}
+
+func (S *dataSystem) Render(T *Template, data interface{}) ([]byte, error) {
+	format := T.Impl.(string)
+
+	V, ok := data.(cue.Value)
+	if !ok {
+		return dataEncoders[format](data)
+	}
+
+	if enc, ok := dataValueEncoders[format]; ok {
+		return enc(V)
+	}
+
+	val, err := decodeValue(V)
+	if err != nil {
+		return nil, err
+	}
+	return dataEncoders[format](val)
+}
+
+// decodeValue keeps integers as integers, rather than float64
+func decodeValue(V cue.Value) (interface{}, error) {
+	src, err := V.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.UseNumber()
+
+	var val interface{}
+	err = dec.Decode(&val)
+	if err != nil {
+		return nil, err
+	}
+	return fromJSONNumbers(val), nil
+}
+
+func fromJSONNumbers(val interface{}) interface{} {
+	switch v := val.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = fromJSONNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = fromJSONNumbers(e)
+		}
+	}
+	return val
+}
+
+func encodeValueJSON(V cue.Value) ([]byte, error) {
+	src, err := V.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = json.Indent(&buf, src, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func encodeValueCUE(V cue.Value) ([]byte, error) {
+	return formatCUE(V.Syntax(cue.Final(), cue.Concrete(true)))
+}
+
+func encodeJSON(data interface{}) ([]byte, error) {
+	bytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes, '\n'), nil
+}
+
+func encodeXML(data interface{}) ([]byte, error) {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("xml data format requires an object, got %T", data)
+	}
+	bytes, err := mxj.Map(m).XmlIndent("", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes, '\n'), nil
+}
+
+// JSON is valid Cue, so we go through it to get Cue syntax
+func encodeCUE(data interface{}) ([]byte, error) {
+	src, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var rt cue.Runtime
+	inst, err := rt.Compile("data.cue", src)
+	if err != nil {
+		return nil, err
+	}
+
+	return formatCUE(inst.Value().Syntax())
+}
+
+// print top-level structs as a file, without the braces
+func formatCUE(node ast.Node) ([]byte, error) {
+	if S, ok := node.(*ast.StructLit); ok {
+		node = &ast.File{Decls: S.Elts}
+	}
+
+	return format.Node(node)
+}
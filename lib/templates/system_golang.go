@@ -0,0 +1,43 @@
+package templates
+
+import (
+	"bytes"
+	"text/template"
+)
+
+type golangSystem struct{}
+
+func (S *golangSystem) Create(T *Template) error {
+	config := T.Config
+
+	// Golang wants helpers before parsing, and catches these errors early
+	t := template.New(T.Name)
+
+	if config.LHS2_D != "{{" {
+		t = t.Delims(config.LHS2_D, config.RHS2_D)
+	}
+
+	AddGolangHelpers(t)
+	if len(config.Helpers) > 0 {
+		t.Funcs(config.Helpers)
+	}
+
+	t, err := t.Parse(T.Source)
+	if err != nil {
+		return err
+	}
+
+	T.T = t
+	return nil
+}
+
+func (S *golangSystem) Render(T *Template, data interface{}) ([]byte, error) {
+	var b bytes.Buffer
+
+	err := T.T.Execute(&b, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
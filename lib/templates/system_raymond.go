@@ -0,0 +1,43 @@
+package templates
+
+import (
+	"fmt"
+
+	"github.com/aymerick/raymond"
+)
+
+type raymondSystem struct{}
+
+func (S *raymondSystem) Create(T *Template) error {
+	config := T.Config
+
+	// Raymond want's to parse before helpers, and catches helper calls during exec
+	content := config.SwitchBefore(T.Source)
+
+	r, err := raymond.Parse(content)
+	if err != nil {
+		return fmt.Errorf("While parsing file: %s\n%w\n", T.Name, err)
+	}
+
+	AddRaymondHelpers(r, config.Helpers)
+
+	T.R = r
+	return nil
+}
+
+func (S *raymondSystem) Render(T *Template, data interface{}) ([]byte, error) {
+	if L, ok := T.Impl.(*raymondLayout); ok {
+		out, err := L.exec(T.R, data)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(out), nil
+	}
+
+	out, err := T.R.Exec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(out), nil
+}
@@ -0,0 +1,117 @@
+package templates_test
+
+import (
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue"
+
+	"github.com/hofstadter-io/hof/lib/templates"
+)
+
+type upperSystem struct{}
+
+func (S *upperSystem) Create(T *templates.Template) error {
+	T.Impl = strings.ToUpper(T.Source)
+	return nil
+}
+
+func (S *upperSystem) Render(T *templates.Template, data interface{}) ([]byte, error) {
+	return []byte(T.Impl.(string)), nil
+}
+
+func TestRegisterTemplateSystem(t *testing.T) {
+	templates.RegisterTemplateSystem("test-upper", &upperSystem{})
+
+	config := &templates.Config{TemplateSystem: "test-upper"}
+	T, err := templates.CreateFromString("upper", "hello", config.TemplateSystem, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := T.Render(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "HELLO" {
+		t.Fatalf("expected %q, got %q", "HELLO", string(out))
+	}
+
+	_, err = templates.CreateFromString("unknown", "hello", "no-such-system", config)
+	if err == nil {
+		t.Fatal("expected error for unknown template system")
+	}
+}
+
+func TestDataSystem(t *testing.T) {
+	data := map[string]interface{}{
+		"name": "hof",
+		"tags": []interface{}{"a", "b"},
+	}
+
+	cases := []struct {
+		Name   string
+		Format string
+		Expect string
+	}{
+		{"out.json", "", "{\n  \"name\": \"hof\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}\n"},
+		{"out.txt", "json", "{\n  \"name\": \"hof\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}\n"},
+		{"out.yaml", "", "name: hof\ntags:\n- a\n- b\n"},
+		{"out.toml", "", "name = \"hof\"\ntags = [\"a\", \"b\"]\n"},
+		{"out.cue", "", "name: \"hof\"\ntags: [\"a\", \"b\"]\n"},
+	}
+
+	for _, c := range cases {
+		config := &templates.Config{TemplateSystem: "data"}
+		T, err := templates.CreateFromString(c.Name, c.Format, "data", config)
+		if err != nil {
+			t.Fatalf("%s: %v", c.Name, err)
+		}
+		out, err := T.Render(data)
+		if err != nil {
+			t.Fatalf("%s: %v", c.Name, err)
+		}
+		if string(out) != c.Expect {
+			t.Errorf("%s: expected\n%q\ngot\n%q", c.Name, c.Expect, string(out))
+		}
+	}
+
+	config := &templates.Config{TemplateSystem: "data"}
+	_, err := templates.CreateFromString("out.unknown", "", "data", config)
+	if err == nil {
+		t.Fatal("expected error for unknown data format")
+	}
+}
+
+func TestDataSystemCueOrder(t *testing.T) {
+	var rt cue.Runtime
+	I, err := rt.Compile("data.cue", "zeta: \"z\"\nalpha: 1\nmid: [true]\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		Name   string
+		Expect string
+	}{
+		{"out.json", "{\n  \"zeta\": \"z\",\n  \"alpha\": 1,\n  \"mid\": [\n    true\n  ]\n}\n"},
+		{"out.yaml", "zeta: z\nalpha: 1\nmid:\n  - true\n"},
+		{"out.cue", "zeta:  \"z\"\nalpha: 1\nmid: [true]\n"},
+		// sorted by the encoder
+		{"out.toml", "alpha = 1\nmid = [true]\nzeta = \"z\"\n"},
+	}
+
+	for _, c := range cases {
+		config := &templates.Config{TemplateSystem: "data"}
+		T, err := templates.CreateFromString(c.Name, "", "data", config)
+		if err != nil {
+			t.Fatalf("%s: %v", c.Name, err)
+		}
+		out, err := T.Render(I.Value())
+		if err != nil {
+			t.Fatalf("%s: %v", c.Name, err)
+		}
+		if string(out) != c.Expect {
+			t.Errorf("%s: expected\n%q\ngot\n%q", c.Name, c.Expect, string(out))
+		}
+	}
+}
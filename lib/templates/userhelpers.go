@@ -0,0 +1,157 @@
+package templates
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"cuelang.org/go/cue"
+)
+
+// A helper declared by a generator in Cue,
+// one of Template or Expr should be set
+type UserHelper struct {
+	// Argument names, bound as data for Template and as fields for Expr
+	Args []string
+
+	// A macro, rendered with the arguments as data
+	//   {{ .name }} in golang, {{ name }} in raymond
+	Template string
+
+	// A Cue expression, evaluated with the arguments
+	//   strings.ToUpper(name), with "strings" in Imports
+	Expr string
+
+	// Packages imported for Expr
+	Imports []string
+}
+
+// The label the expression result is placed under
+const userHelperResult = "$hof_helper_result"
+
+// Helper and argument names are called from templates and bound as Cue fields,
+// so they must be identifiers in all of them. text/template panics otherwise.
+var helperIdent = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Creates the helper functions for a set of user defined helpers.
+// Macros are parsed with the config's template system and can call
+// any other helper, including the other user defined helpers.
+func CreateUserHelpers(defs map[string]*UserHelper, config *Config) (map[string]interface{}, error) {
+	helpers := make(map[string]interface{}, len(defs))
+	macros := make(map[string]*Template)
+
+	for name, H := range defs {
+		name := name
+		if !helperIdent.MatchString(name) {
+			return nil, fmt.Errorf("Helper %q is not a valid name, use letters, digits, and '_', starting with a letter", name)
+		}
+		for _, arg := range H.Args {
+			if !helperIdent.MatchString(arg) {
+				return nil, fmt.Errorf("Helper %q argument %q is not a valid name, use letters, digits, and '_', starting with a letter", name, arg)
+			}
+		}
+		if H.Template != "" && H.Expr != "" {
+			return nil, fmt.Errorf("Helper %q cannot specify both Template and Expr", name)
+		}
+
+		if H.Expr != "" {
+			fn, err := createExprHelper(name, H)
+			if err != nil {
+				return nil, err
+			}
+			helpers[name] = fn
+			continue
+		}
+
+		if H.Template == "" {
+			return nil, fmt.Errorf("Helper %q must specify one of Template or Expr", name)
+		}
+
+		helpers[name] = makeHelperFunc(H.Args, reflect.TypeOf(""), func(data map[string]interface{}) interface{} {
+			M, ok := macros[name]
+			if !ok {
+				return fmt.Sprintf("ERROR: helper %q used before it was created", name)
+			}
+			out, err := M.Render(data)
+			if err != nil {
+				return fmt.Sprintf("ERROR: in helper %q: %v", name, err)
+			}
+			return string(out)
+		})
+	}
+
+	// macros can use all of the helpers, so create them last
+	macroConfig := *config
+	macroConfig.Helpers = helpers
+	for name, H := range defs {
+		if H.Template == "" {
+			continue
+		}
+		T, err := CreateFromString("helper:"+name, H.Template, macroConfig.TemplateSystem, &macroConfig)
+		if err != nil {
+			return nil, fmt.Errorf("While parsing helper %q\n%w", name, err)
+		}
+		macros[name] = T
+	}
+
+	return helpers, nil
+}
+
+func createExprHelper(name string, H *UserHelper) (interface{}, error) {
+	var b strings.Builder
+	for _, imp := range H.Imports {
+		fmt.Fprintf(&b, "import %q\n", imp)
+	}
+	for _, arg := range H.Args {
+		fmt.Fprintf(&b, "%s: _\n", arg)
+	}
+	fmt.Fprintf(&b, "%q: %s\n", userHelperResult, H.Expr)
+
+	rt := &cue.Runtime{}
+	I, err := rt.Compile("helper:"+name, b.String())
+	if err != nil {
+		return nil, fmt.Errorf("While compiling helper %q\n%w", name, err)
+	}
+
+	fn := makeHelperFunc(H.Args, reflect.TypeOf((*interface{})(nil)).Elem(), func(data map[string]interface{}) interface{} {
+		filled, err := I.Fill(data)
+		if err != nil {
+			return fmt.Sprintf("ERROR: in helper %q: %v", name, err)
+		}
+
+		var out interface{}
+		err = filled.Lookup(userHelperResult).Decode(&out)
+		if err != nil {
+			return fmt.Sprintf("ERROR: in helper %q: %v", name, err)
+		}
+		return out
+	})
+
+	return fn, nil
+}
+
+// Builds a function with one interface{} parameter per arg, so that
+// both golang and raymond check the number of arguments for us
+func makeHelperFunc(args []string, ret reflect.Type, impl func(map[string]interface{}) interface{}) interface{} {
+	in := make([]reflect.Type, len(args))
+	for i := range args {
+		in[i] = reflect.TypeOf((*interface{})(nil)).Elem()
+	}
+	ft := reflect.FuncOf(in, []reflect.Type{ret}, false)
+
+	fn := reflect.MakeFunc(ft, func(vals []reflect.Value) []reflect.Value {
+		data := make(map[string]interface{}, len(args))
+		for i, arg := range args {
+			data[arg] = vals[i].Interface()
+		}
+
+		out := reflect.New(ret).Elem()
+		if r := impl(data); r != nil {
+			out.Set(reflect.ValueOf(r))
+		}
+		return []reflect.Value{out}
+	})
+
+	return fn.Interface()
+}
@@ -0,0 +1,134 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hofstadter-io/hof/lib/templates"
+)
+
+func createUserHelpersConfig(system string, defs map[string]*templates.UserHelper) (*templates.Config, error) {
+	config := &templates.Config{
+		TemplateSystem: system,
+		LHS2_D:         "{{",
+		RHS2_D:         "}}",
+		LHS3_D:         "{{{",
+		RHS3_D:         "}}}",
+	}
+
+	helpers, err := templates.CreateUserHelpers(defs, config)
+	if err != nil {
+		return nil, err
+	}
+	config.Helpers = helpers
+	return config, nil
+}
+
+func TestUserHelpers(t *testing.T) {
+	cases := []struct {
+		System string
+		Defs   map[string]*templates.UserHelper
+		Source string
+		Expect string
+	}{
+		{
+			System: "golang",
+			Defs: map[string]*templates.UserHelper{
+				"getter": {Args: []string{"name", "type"}, Template: `func Get{{ camelT .name }}() {{ .type }}`},
+			},
+			Source: `{{ getter .Name "string" }}`,
+			Expect: "func GetUser() string",
+		},
+		{
+			System: "raymond",
+			Defs: map[string]*templates.UserHelper{
+				"getter": {Args: []string{"name", "type"}, Template: `func Get{{camelT name}}() {{type}}`},
+			},
+			Source: `{{getter Name "string"}}`,
+			Expect: "func GetUser() string",
+		},
+		{
+			System: "golang",
+			Defs: map[string]*templates.UserHelper{
+				"label":   {Args: []string{"name"}, Expr: `"\(name)_total"`},
+				"wrapped": {Args: []string{"name"}, Template: `[{{ label .name }}]`},
+			},
+			Source: `{{ label .Name }} {{ wrapped "x" }}`,
+			Expect: "user_total [x_total]",
+		},
+		{
+			System: "golang",
+			Defs: map[string]*templates.UserHelper{
+				"shout": {Args: []string{"name"}, Imports: []string{"strings"}, Expr: `strings.ToUpper(name)`},
+			},
+			Source: `{{ shout .Name }}`,
+			Expect: "USER",
+		},
+		{
+			System: "raymond",
+			Defs: map[string]*templates.UserHelper{
+				"double": {Args: []string{"n"}, Expr: `n * 2`},
+			},
+			Source: `{{double Count}}`,
+			Expect: "6",
+		},
+		{
+			System: "golang",
+			Defs: map[string]*templates.UserHelper{
+				"upper": {Args: []string{"s"}, Template: `<{{ .s }}>`},
+				"lower": {Args: []string{"s"}, Template: `>{{ .s }}<`},
+			},
+			Source: `{{ upper .Name }}{{ lower .Name }}`,
+			Expect: "<user>>user<",
+		},
+	}
+
+	for _, c := range cases {
+		config, err := createUserHelpersConfig(c.System, c.Defs)
+		assert.Nil(t, err, c.Source)
+		if err != nil {
+			continue
+		}
+
+		T, err := templates.CreateFromString("test", c.Source, c.System, config)
+		assert.Nil(t, err, c.Source)
+		if err != nil {
+			continue
+		}
+
+		out, err := T.Render(helperData)
+		assert.Nil(t, err, c.Source)
+		assert.Equal(t, c.Expect, string(out), c.Source)
+	}
+}
+
+func TestUserHelpersErrors(t *testing.T) {
+	_, err := createUserHelpersConfig("golang", map[string]*templates.UserHelper{
+		"both": {Template: "x", Expr: "1"},
+	})
+	assert.NotNil(t, err)
+
+	_, err = createUserHelpersConfig("golang", map[string]*templates.UserHelper{
+		"neither": {},
+	})
+	assert.NotNil(t, err)
+
+	_, err = createUserHelpersConfig("golang", map[string]*templates.UserHelper{
+		"bad": {Args: []string{"a"}, Expr: "a +"},
+	})
+	assert.NotNil(t, err)
+
+	// names which would panic in text/template, or are not Cue fields
+	for _, system := range []string{"golang", "raymond"} {
+		_, err = createUserHelpersConfig(system, map[string]*templates.UserHelper{
+			"my-helper": {Template: "x"},
+		})
+		assert.NotNil(t, err, system)
+
+		_, err = createUserHelpersConfig(system, map[string]*templates.UserHelper{
+			"helper": {Args: []string{"an-arg"}, Expr: "1"},
+		})
+		assert.NotNil(t, err, system)
+	}
+}
@@ -0,0 +1,196 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+
+	"github.com/hofstadter-io/hof/lib/yagu"
+)
+
+// The url for these can be any git remote, https://, ssh://, git@host:path, file://, or a local path
+
+// ListRemote lists the references of a remote, like 'git ls-remote'
+func ListRemote(url string) ([]*plumbing.Reference, error) {
+	rc := &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	}
+	remote := gogit.NewRemote(memory.NewStorage(), rc)
+
+	return remote.List(&gogit.ListOptions{Auth: remoteAuth(url)})
+}
+
+// CloneVersion clones a remote into memory with the worktree at ver,
+// which is a tag, a commit hash, or "" and "v0.0.0" for the default branch.
+// Tags without the leading 'v' are found too.
+func CloneVersion(url, ver string) (*GitRepo, error) {
+	co := &gogit.CloneOptions{
+		URL:  url,
+		Auth: remoteAuth(url),
+	}
+
+	// commits are checked out after a full clone
+	commit := ""
+
+	refs, err := ListRemote(url)
+	if err != nil {
+		return nil, fmt.Errorf("While listing %s\n%w\n", url, err)
+	}
+
+	if ver == "" || ver == "v0.0.0" {
+		branch := defaultBranch(refs)
+		if branch == "" {
+			return nil, fmt.Errorf("Did not find the default branch of %s", url)
+		}
+		co.ReferenceName = branch
+		co.SingleBranch = true
+		co.Depth = 1
+	} else {
+		tag := findTag(ver, refs)
+		switch {
+		case tag != "":
+			co.ReferenceName = plumbing.ReferenceName(tag)
+			co.SingleBranch = true
+			co.Depth = 1
+		case IsCommit(ver):
+			commit = ver
+		default:
+			return nil, fmt.Errorf("Did not find tag or commit %q in %s", ver, url)
+		}
+	}
+
+	st := memory.NewStorage()
+	fs := memfs.New()
+	r, err := gogit.Clone(st, fs, co)
+	if err != nil {
+		return nil, fmt.Errorf("While cloning %s @ %s\n%w\n", url, ver, err)
+	}
+
+	if commit != "" {
+		hash, err := resolveCommit(r, commit)
+		if err != nil {
+			return nil, fmt.Errorf("While resolving commit in %s\n%w\n", url, err)
+		}
+		wt, err := r.Worktree()
+		if err != nil {
+			return nil, err
+		}
+		err = wt.Checkout(&gogit.CheckoutOptions{Hash: hash, Force: true})
+		if err != nil {
+			return nil, fmt.Errorf("While checking out %s in %s\n%w\n", commit, url, err)
+		}
+	}
+
+	return &GitRepo{
+		Store: st,
+		FS:    fs,
+		Repo:  r,
+	}, nil
+}
+
+// resolveCommit finds a full or abbreviated commit hash
+func resolveCommit(r *gogit.Repository, commit string) (plumbing.Hash, error) {
+	if len(commit) == 40 {
+		h := plumbing.NewHash(commit)
+		_, err := r.CommitObject(h)
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("Did not find commit %q\n%w\n", commit, err)
+		}
+		return h, nil
+	}
+
+	iter, err := r.CommitObjects()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	var found []plumbing.Hash
+	err = iter.ForEach(func(c *object.Commit) error {
+		if strings.HasPrefix(c.Hash.String(), commit) {
+			found = append(found, c.Hash)
+		}
+		return nil
+	})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	switch len(found) {
+	case 0:
+		return plumbing.ZeroHash, fmt.Errorf("Did not find commit %q", commit)
+	case 1:
+		return found[0], nil
+	default:
+		return plumbing.ZeroHash, fmt.Errorf("Commit %q is ambiguous", commit)
+	}
+}
+
+// defaultBranch is where the remote HEAD points,
+// by name when advertised, otherwise the branch with the same commit
+func defaultBranch(refs []*plumbing.Reference) plumbing.ReferenceName {
+	var head *plumbing.Reference
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD {
+			head = ref
+		}
+	}
+	if head == nil {
+		return ""
+	}
+	if head.Type() == plumbing.SymbolicReference {
+		return head.Target()
+	}
+	for _, ref := range refs {
+		if ref.Name().IsBranch() && ref.Hash() == head.Hash() {
+			return ref.Name()
+		}
+	}
+	return ""
+}
+
+func findTag(ver string, refs []*plumbing.Reference) string {
+	names := []string{"refs/tags/" + ver}
+	if strings.HasPrefix(ver, "v") {
+		names = append(names, "refs/tags/"+strings.TrimPrefix(ver, "v"))
+	}
+	for _, name := range names {
+		for _, ref := range refs {
+			if ref.Name().String() == name {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// IsCommit reports whether ver looks like a, possibly abbreviated, commit hash
+func IsCommit(ver string) bool {
+	if len(ver) < 7 || len(ver) > 40 {
+		return false
+	}
+	for _, c := range ver {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Tokens from the environment are only for http remotes,
+// ssh uses the agent, and file remotes need nothing
+func remoteAuth(url string) transport.AuthMethod {
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return nil
+	}
+
+	co := &gogit.CloneOptions{URL: url}
+	yagu.SetupGitAuth(url, "", co)
+	return co.Auth
+}
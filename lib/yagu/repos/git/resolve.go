@@ -0,0 +1,162 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"golang.org/x/mod/semver"
+)
+
+// CommitInfo is what a ref resolved to, with the semver tags needed for versioning
+type CommitInfo struct {
+	Hash string
+	Time time.Time
+
+	// semver tags on the commit itself
+	Tags []string
+	// the greatest semver tag on an ancestor of the commit, if any
+	Base string
+}
+
+// ResolveRef finds the commit for a branch, a non-semver tag, a full or abbreviated
+// commit hash, or "" and "HEAD" for the default branch. The history is cloned,
+// into memory, so the tags of ancestors can be found.
+func ResolveRef(url, ref string) (*CommitInfo, error) {
+	refs, err := ListRemote(url)
+	if err != nil {
+		return nil, fmt.Errorf("While listing %s\n%w\n", url, err)
+	}
+
+	co := &gogit.CloneOptions{
+		URL:        url,
+		Auth:       remoteAuth(url),
+		NoCheckout: true,
+		Tags:       gogit.AllTags,
+	}
+
+	commit := ""
+	switch {
+	case ref == "" || ref == "HEAD":
+		co.ReferenceName = defaultBranch(refs)
+		if co.ReferenceName == "" {
+			return nil, fmt.Errorf("Did not find the default branch of %s", url)
+		}
+		co.SingleBranch = true
+	case hasRef(refs, plumbing.NewBranchReferenceName(ref)):
+		co.ReferenceName = plumbing.NewBranchReferenceName(ref)
+		co.SingleBranch = true
+	case findTag(ref, refs) != "":
+		co.ReferenceName = plumbing.ReferenceName(findTag(ref, refs))
+		co.SingleBranch = true
+	case IsCommit(ref):
+		commit = ref
+	default:
+		return nil, fmt.Errorf("Did not find branch, tag, or commit %q in %s", ref, url)
+	}
+
+	r, err := gogit.Clone(memory.NewStorage(), memfs.New(), co)
+	if err != nil {
+		return nil, fmt.Errorf("While cloning %s @ %s\n%w\n", url, ref, err)
+	}
+
+	var hash plumbing.Hash
+	if commit != "" {
+		hash, err = resolveCommit(r, commit)
+		if err != nil {
+			return nil, fmt.Errorf("While resolving commit in %s\n%w\n", url, err)
+		}
+	} else {
+		head, err := r.Head()
+		if err != nil {
+			return nil, err
+		}
+		hash = head.Hash()
+	}
+
+	C, err := r.CommitObject(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := semverTags(r)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &CommitInfo{
+		Hash: hash.String(),
+		Time: C.Committer.When.UTC(),
+		Tags: tags[hash],
+	}
+
+	// the greatest tag in the history
+	iter, err := r.Log(&gogit.LogOptions{From: hash})
+	if err != nil {
+		return nil, err
+	}
+	err = iter.ForEach(func(c *object.Commit) error {
+		if c.Hash == hash {
+			return nil
+		}
+		for _, t := range tags[c.Hash] {
+			if info.Base == "" || semver.Compare(t, info.Base) > 0 {
+				info.Base = t
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// semverTags maps commits to their semver tags, with a leading 'v'
+func semverTags(r *gogit.Repository) (map[plumbing.Hash][]string, error) {
+	tags := map[plumbing.Hash][]string{}
+
+	iter, err := r.Tags()
+	if err != nil {
+		return nil, err
+	}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if !strings.HasPrefix(name, "v") {
+			name = "v" + name
+		}
+		if !semver.IsValid(name) {
+			return nil
+		}
+
+		// annotated tags point at a tag object
+		hash := ref.Hash()
+		if T, err := r.TagObject(hash); err == nil {
+			C, err := T.Commit()
+			if err != nil {
+				return nil
+			}
+			hash = C.Hash
+		}
+
+		tags[hash] = append(tags[hash], name)
+		return nil
+	})
+
+	return tags, err
+}
+
+func hasRef(refs []*plumbing.Reference, name plumbing.ReferenceName) bool {
+	for _, ref := range refs {
+		if ref.Name() == name {
+			return true
+		}
+	}
+	return false
+}
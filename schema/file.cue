@@ -16,6 +16,28 @@ package schema
   // Relative name from TemplatesDir
   TemplateName: string | *""
 
+  // Named layout (base template) to render the template within
+  //   the template overrides the layout's blocks and its other output fills the "content" block
+  Layout: string | *""
+
+  // Data files, serialized with the 'data' template system, need no template
+  //   DataFormat empty infers the format from the Filepath extension
+  //   Value defaults to In when not set
+  DataFormat: *"" | "json" | "yaml" | "toml" | "cue" | "xml"
+  Value?: _
+
+  // Output file permissions (e.g. 0o755 for scripts), kept in sync when set.
+  // 0 creates files with 0o644 and keeps any permissions the user sets after.
+  Mode: int | *0
+
+  // Output a symlink to this target instead of a file
+  Symlink: string | *""
+
+  // Binary content, as base64 or a file to copy relative to the generator package
+  //   binary files are not rendered and are never merged with user changes
+  Base64: string | *""
+  Source: string | *""
+
   // Include Common attributes
   //  '.' will bre replaced by generator defaults
 
@@ -23,3 +45,12 @@ package schema
 
   // WARNING, intentionally closed to prevent user error when creating GenFiles
 }
+
+// A static file may be just its content, or this
+#HofStaticFile: {
+  Content: string | *""
+  Mode:    int | *0
+  Symlink: string | *""
+  Base64:  string | *""
+  Source:  string | *""
+}
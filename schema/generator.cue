@@ -9,9 +9,17 @@ package schema
   // "Global" input, merged with out replacing onto the files
   In: { ... } | * {...}
 
+  // Data files outside of Cue, loaded into In under their name
+  InData: { [Name=string]: #HofDataSource }
+
   // The list fo files for hof to generate
   Out: [...#HofGeneratorFile] | *[...]
 
+  // Names of generators (their Cue labels) which must run before this one,
+  // i.e. when this generator consumes their output. Two generators writing
+  // the same file is an error, regardless of order.
+  DependsOn: [...string] | *[]
+
   //  Attribute expansions are used to turn the @attributes
 	//  into something you can use in the templates
 	//  (i.e. we need to add it to the data model after Cue processing)
@@ -28,9 +36,15 @@ package schema
   // under its name for reference in GenFiles  and partials in templates
   NamedTemplates: { [Name=string]: string }
   NamedPartials:  { [Name=string]: string }
+  // Base templates with overridable blocks, files choose one with Layout
+  NamedLayouts:   { [Name=string]: string }
+  // User defined helpers, available to all templates and partials in this generator,
+  // named by identifiers so they can be called from templates
+  Helpers: [=~"^[A-Za-z][A-Za-z0-9_]*$"]: #TemplateHelper
+
   // Static files are available for pure cue generators that want to have static files
-  // These should be named by their filepath, but be the content of the file
-  StaticFiles: { [Name=string]:  string }
+  // These should be named by their filepath, and be the content of the file or a #HofStaticFile
+  StaticFiles: { [Name=string]:  string | #HofStaticFile }
 
   //
   // For file based generators
@@ -46,6 +60,9 @@ package schema
   // Base directory of partial templatess to load
   PartialsDir: string | * "/partials"
 
+  // Base directory of layout templates to load
+  LayoutsDir: string | * "/layouts"
+
   // Base directory of entrypoint templates to load
   TemplatesDir: string | * "/templates"
 
@@ -57,3 +74,18 @@ package schema
   // Open for whatever else you may need as a generator writer
   ...
 } 
+
+// A data file for a generator's In
+#HofDataSource: {
+  // Relative to where hof is run
+  Path: string
+  // Empty infers the format from the Path extension
+  Format: *"" | "json" | "yaml" | "yml" | "csv" | "toml" | "xml" | "sqlite" | "db"
+  // sqlite only, the rows become a list of objects.
+  // Reading sqlite uses the sqlite3 command, version 3.33 or later
+  Query?: string
+
+  // The data is validated against this, and defaults are filled in.
+  // CSV rows are objects keyed by the header, with values typed like Cue literals.
+  #Schema?: _
+}
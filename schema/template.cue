@@ -1,8 +1,9 @@
 package schema
 
 #TemplateConfigReplacible: {
-  // System params
-  TemplateSystem: *"." | "golang" | "raymond"
+  // System params, a registered template system, "." inherits the generator's
+  //   golang, raymond, or data for files rendered from their value
+  TemplateSystem: string | *"."
 
   //
   // Template delimiters
@@ -50,8 +51,11 @@ package schema
 
 #DefaultTemplateConfig: {
   // Include Common attributes
-  // System params
-  TemplateSystem: *"golang" | "raymond"
+  // System params, a registered template system, i.e. golang or raymond
+  //   data is only for files, set on their TemplateConfig
+  //   raymond passes variadic helper arguments by key, in key order,
+  //   so {{list z="x" a="y"}} is ["y", "x"], name them in order {{list a="x" b="y"}}
+  TemplateSystem: string & !="data" | *"golang"
 
   //
   // Template delimiters
@@ -97,3 +101,20 @@ package schema
   RHS3_T: string | *"#_hof_r3_#"
 }
 
+
+// A user defined template helper, set one of Template or Expr
+#TemplateHelper: {
+  // Argument names, in call order, identifiers like the helper's name
+  Args: [...=~"^[A-Za-z][A-Za-z0-9_]*$"] | *[]
+
+  // A macro, a small template rendered with the arguments as data
+  //   {{ .name }} in golang, {{ name }} in raymond
+  Template: string | *""
+
+  // A Cue expression, evaluated with the arguments as fields
+  //   "\(name)_\(kind)"
+  Expr: string | *""
+
+  // Packages the Expr uses, i.e. ["strings"] for strings.ToUpper(name)
+  Imports: [...string] | *[]
+}